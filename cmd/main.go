@@ -2,60 +2,207 @@ package main
 
 import (
 	app "avito.go/internal/app"
+	"avito.go/internal/blob"
 	"avito.go/internal/config"
+	"avito.go/internal/metrics"
+	"avito.go/internal/middleware"
 	"avito.go/internal/routes"
+	"avito.go/internal/scheduler"
 	"avito.go/internal/storage"
+	"avito.go/internal/storage/memory"
+	"avito.go/internal/tracing"
 	"avito.go/pkg/logger"
 	"context"
+	"database/sql"
 	"fmt"
+	"go.uber.org/zap"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
-	cfg, err := config.Load()
+	err := logger.InitLogger()
 	if err != nil {
-		log.Fatalf("Failed to load environment variables: %v", err)
+		fmt.Println("Error init logger ", err)
 	}
 
-	err = logger.InitLogger()
+	// Подкоманде migrate нужны только настройки базы, настройки сервера для неё не обязательны
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cfg, err := config.LoadDatabase()
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		if err = runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error init logger ", err)
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	DatabaseDSN := storage.GetDatabaseDSN(*cfg)
+	if err = run(cfg); err != nil {
+		logger.Log.Error("service stopped with error", zap.Error(err))
+		logger.Log.Sync()
+		os.Exit(1)
+	}
+	logger.Log.Sync()
+}
 
-	fmt.Println(DatabaseDSN)
+// run запускает сервис и работает до SIGINT или SIGTERM.
+// При остановке сервер дожидается обрабатываемых запросов не дольше ShutdownTimeout,
+// затем останавливаются фоновые задачи, дописываются span'ы и закрывается пул соединений.
+func run(cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	db := storage.NewStorage(DatabaseDSN)
-	defer db.DB.Close()
+	// background - контекст фоновых задач; отменяется при остановке, workers ждёт их завершения
+	background, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var workers sync.WaitGroup
 
-	//TODO: покрыть тестами
-	//TODO: auth изменить логгирование
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
 
-	A := app.NewApp(db)
-	r := routes.NewRouter(*A)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingExporter, cfg.TracingServiceName)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
+	blobs, err := blob.NewLocal(cfg.AttachmentDir)
+	if err != nil {
+		return fmt.Errorf("failed to open attachment directory: %w", err)
+	}
+
+	auth, err := middleware.NewAuth(cfg.AuthSecret, cfg.AuthTokenTTL, cfg.AuthLegacyUsername)
+	if err != nil {
+		return fmt.Errorf("failed to set up authentication: %w", err)
+	}
+
+	A := app.NewApp(store, auth, blobs, cfg.AttachmentMaxSize)
+	r := routes.NewRouter(*A, auth, middleware.NewAccessLog(cfg.AccessLogSampleRate))
 
 	srv := http.Server{
 		Addr:    cfg.ServerAddress,
 		Handler: r,
 	}
 
-	done := make(chan struct{})
+	if cfg.SchedulerInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			scheduler.New(store, cfg.SchedulerInterval).Run(background)
+		}()
+	}
+
+	serveErr := make(chan error, 1)
 	go func() {
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, os.Interrupt)
-		<-sigs
-		if err = srv.Shutdown(context.Background()); err != nil {
-			log.Printf("HTTP server Shutdown: %v", err)
-		}
-		close(done)
+		serveErr <- srv.ListenAndServe()
 	}()
+	logger.Log.Info("server started", zap.String("address", cfg.ServerAddress))
 
-	err = srv.ListenAndServe()
+	select {
+	case err = <-serveErr:
+		err = fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+		logger.Log.Info("shutting down", zap.Duration("timeout", cfg.ShutdownTimeout))
+		err = shutdown(&srv, cfg.ShutdownTimeout)
+	}
+
+	cancel()
+	workers.Wait()
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelFlush()
+	if errTracing := shutdownTracing(flushCtx); errTracing != nil {
+		logger.Log.Error("failed to flush spans", zap.Error(errTracing))
+	}
+	return err
+}
+
+// openStorage создаёт хранилище и проверяет, что оно работает: за StartupTimeout база должна ответить
+// и схема должна быть доведена до последней миграции, иначе сервис не стартует.
+func openStorage(ctx context.Context, cfg *config.Config) (storage.Storage, error) {
+	var store storage.Storage
+	switch cfg.StorageType {
+	case "memory":
+		mem := memory.New()
+		if cfg.StorageSeed != "" {
+			if err := mem.LoadFile(cfg.StorageSeed); err != nil {
+				return nil, fmt.Errorf("failed to load storage seed: %w", err)
+			}
+		}
+		store = mem
+	case "postgres":
+		DatabaseDSN, err := storage.GetDatabaseDSN(*cfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Log.Info("connecting to database", zap.String("dsn", storage.RedactDSN(DatabaseDSN)))
+
+		db, err := storage.NewStorage(DatabaseDSN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		startupCtx, cancel := context.WithTimeout(ctx, cfg.StartupTimeout)
+		defer cancel()
+		if err = db.WaitPing(startupCtx, time.Second); err != nil {
+			db.Close()
+			return nil, err
+		}
+		if err = db.MigrateUp(startupCtx, cfg.MigrateRetryInterval); err != nil {
+			db.Close()
+			return nil, err
+		}
+		if err = metrics.RegisterDB(db.DB, "postgres"); err != nil {
+			logger.Log.Error("failed to register DB metrics", zap.Error(err))
+		}
+		store = db
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.StorageType)
+	}
+	return metrics.NewStorage(tracing.NewStorage(store)), nil
+}
+
+// shutdown перестаёт принимать соединения и ждёт обрабатываемые запросы не дольше timeout
+func shutdown(srv *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		srv.Close()
+		return fmt.Errorf("HTTP server shutdown: %w", err)
+	}
+	return nil
+}
+
+// runMigrate обрабатывает подкоманду "migrate up|down|status" и завершает работу без запуска сервера
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 || !slices.Contains(storage.MigrateCommands, args[0]) {
+		return fmt.Errorf("usage: migrate %s", strings.Join(storage.MigrateCommands, "|"))
+	}
+
+	DatabaseDSN, err := storage.GetDatabaseDSN(*cfg)
 	if err != nil {
-		log.Println(err)
+		return err
 	}
+	db, err := sql.Open("pgx", DatabaseDSN)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return storage.Migrate(context.Background(), db, args[0], args[1:]...)
 }
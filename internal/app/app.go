@@ -1,41 +1,49 @@
 package app
 
 import (
+	"avito.go/internal/app/services/attachment"
+	"avito.go/internal/app/services/audit"
+	"avito.go/internal/app/services/auth"
 	"avito.go/internal/app/services/bid"
 	"avito.go/internal/app/services/checker"
+	"avito.go/internal/app/services/employee"
+	"avito.go/internal/app/services/organization"
 	"avito.go/internal/app/services/tender"
-	"avito.go/internal/models"
-	"context"
+	"avito.go/internal/blob"
+	"avito.go/internal/middleware"
+	"avito.go/internal/storage"
 )
 
-type Storage interface {
-	Add(ctx context.Context, entity interface{}, username string, key int) error
-	GetMy(ctx context.Context, limit, offset int, username string, key int) (interface{}, error)
-	GetStatus(ctx context.Context, Id, username string, key int) (string, error)
-	UpdateStatus(ctx context.Context, Id, status, username string, key int) (interface{}, error)
-	RollbackVersion(ctx context.Context, Id, version, username string, key int) (interface{}, error)
-
-	GetTenders(ctx context.Context, limit, offset int, serviceType []string) ([]models.Tender, error)
-	EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string) (models.Tender, error)
-
-	GetTenderBids(ctx context.Context, tenderId, username string, limit, offset int) ([]models.Bid, error)
-	SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) // Отправить решение по биду
-	EditBid(ctx context.Context, bidId, username, bidName, description, status string) (models.Bid, error)
-
-	AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) // отправить отзыв по предложению.
-	GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, limit, offset int) ([]models.FeedBack, error)
-}
-
 type App struct {
 	bid.BidController
 	tender.TenderController
 	checker.CheckerController
+	auth.AuthController
+	organization.OrganizationController
+	employee.EmployeeController
+	audit.AuditController
+	attachment.AttachmentController
 }
 
-func NewApp(storage Storage) *App {
-	bid := bid.BidController{Storage: storage}
-	tender := tender.TenderController{Storage: storage}
-	checker := checker.CheckerController{}
-
-	return &App{BidController: bid, TenderController: tender, CheckerController: checker}
+// NewApp собирает контроллеры; blobs хранит содержимое приложенных файлов, maxAttachmentSize - предел размера одного файла
+func NewApp(store storage.Storage, authenticator *middleware.Auth, blobs blob.Store, maxAttachmentSize int64) *App {
+	bid := bid.BidController{Storage: store, Employees: store}
+	tender := tender.TenderController{Storage: store}
+	checker := checker.CheckerController{Storage: store}
+	auth := auth.AuthController{Storage: store, Auth: authenticator}
+	organization := organization.OrganizationController{Storage: store}
+	employee := employee.EmployeeController{Storage: store}
+	audit := audit.AuditController{Storage: store}
+	attachment := attachment.AttachmentController{Storage: store, Blobs: blobs, MaxSize: maxAttachmentSize}
+
+	return &App{
+		BidController:          bid,
+		TenderController:       tender,
+		CheckerController:      checker,
+		AuthController:         auth,
+		OrganizationController: organization,
+		EmployeeController:     employee,
+		AuditController:        audit,
+		AttachmentController:   attachment,
+	}
 }
@@ -0,0 +1,40 @@
+package attachment
+
+import (
+	"avito.go/internal/blob"
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"net/http"
+)
+
+// AttachmentController принимает и отдаёт файлы тендеров и предложений.
+// Сведения о файлах хранит Storage, содержимое - Blobs; MaxSize ограничивает размер одного файла.
+type AttachmentController struct {
+	Storage storage.AttachmentStorage
+	Blobs   blob.Store
+	MaxSize int64
+}
+
+type ResponseDataAttachment struct {
+	Result models.Attachment
+}
+
+type ResponseDataAttachments struct {
+	Result []models.Attachment
+}
+
+func (ac *AttachmentController) TenderAttachmentUpload(w http.ResponseWriter, r *http.Request) {
+	ac.upload(w, r, models.AuditTender, "tenderId")
+}
+
+func (ac *AttachmentController) BidAttachmentUpload(w http.ResponseWriter, r *http.Request) {
+	ac.upload(w, r, models.AuditBid, "bidId")
+}
+
+func (ac *AttachmentController) TenderAttachments(w http.ResponseWriter, r *http.Request) {
+	ac.list(w, r, models.AuditTender, "tenderId")
+}
+
+func (ac *AttachmentController) BidAttachments(w http.ResponseWriter, r *http.Request) {
+	ac.list(w, r, models.AuditBid, "bidId")
+}
@@ -0,0 +1,62 @@
+package attachment
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/problem"
+	"avito.go/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ChecksumHeader - SHA-256 содержимого в hex, как в поле checksum сведений о файле.
+// Content-Digest не подходит: при сжатии ответа он считается по сжатым байтам.
+const ChecksumHeader = "X-Checksum-Sha256"
+
+type RequestDataDownload struct {
+	AttachmentID string `validate:"required,max=100"`
+	Username     string `validate:"required"`
+}
+
+// AttachmentDownload отдаёт содержимое файла. Права те же, что на просмотр сущности, к которой он приложен;
+// контрольная сумма передаётся в ChecksumHeader, чтобы клиент мог проверить скачанное.
+func (ac *AttachmentController) AttachmentDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
+		return
+	}
+
+	req := RequestDataDownload{
+		AttachmentID: mux.Vars(r)["attachmentId"],
+		Username:     middleware.AuthUsername(r, r.URL.Query().Get("username")),
+	}
+	validate := validator.New()
+	if validate.Struct(req) != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	attachment, err := ac.Storage.GetAttachment(r.Context(), req.AttachmentID, req.Username)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	content, err := ac.Blobs.Get(r.Context(), attachment.ID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set(ChecksumHeader, attachment.Checksum)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err = io.Copy(w, content); err != nil {
+		logger.FromContext(r.Context()).Error("failed to send attachment", zap.String("attachment_id", attachment.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,194 @@
+package attachment
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"avito.go/pkg/logger"
+	ID "avito.go/pkg/uuid"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// multipartOverhead - запас на заголовки частей и текстовые поля формы сверх размера файла
+const multipartOverhead = 1 << 20
+
+type RequestDataUpload struct {
+	EntityID string `validate:"required,max=100"`
+	Username string `validate:"required"`
+	Filename string `validate:"required,max=255"`
+	Checksum string `validate:"omitempty,len=64,hexadecimal"` // Необязательная SHA-256 файла от клиента, сверяется с загруженным содержимым
+}
+
+// upload принимает multipart/form-data с файлом в поле file и необязательной SHA-256 в поле checksum.
+// Файл привязывается к текущей версии сущности; при любой ошибке после записи содержимое удаляется.
+func (ac *AttachmentController) upload(w http.ResponseWriter, r *http.Request, entityType, idVar string) {
+	if r.Method != http.MethodPost {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPost))
+		return
+	}
+
+	req := RequestDataUpload{
+		EntityID: mux.Vars(r)[idVar],
+		Username: middleware.AuthUsername(r, r.URL.Query().Get("username")),
+	}
+	validate := validator.New()
+	if validate.StructPartial(req, "EntityID", "Username") != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	// Права проверяются до чтения тела, чтобы не принимать файл, который всё равно не будет сохранён
+	if err := ac.Storage.CanAttach(r.Context(), entityType, req.EntityID, req.Username); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	attachment := models.Attachment{
+		ID:         ID.GenerateCorrelationID(),
+		EntityType: entityType,
+		EntityID:   req.EntityID,
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, ac.MaxSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+
+	// При сохранении сведений о файле права проверяются повторно; содержимое к этому моменту уже записано, поэтому удаляется при любой ошибке
+	stored := false
+	key := attachment.ID
+	defer func() {
+		if stored {
+			if errDelete := ac.Blobs.Delete(r.Context(), key); errDelete != nil {
+				logger.FromContext(r.Context()).Error("failed to delete attachment content", zap.String("attachment_id", key), zap.Error(errDelete))
+			}
+		}
+	}()
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			problem.Write(w, r, bodyError(err))
+			return
+		}
+
+		switch part.FormName() {
+		case "file":
+			if stored {
+				problem.Write(w, r, problem.ErrInvalidBody)
+				return
+			}
+			req.Filename = part.FileName()
+			attachment.Filename = req.Filename
+			attachment.ContentType = contentType(part)
+
+			content := &countingReader{r: part, limit: ac.MaxSize, hash: sha256.New()}
+			if err = ac.Blobs.Put(r.Context(), key, content); err != nil {
+				// ошибка чтения тела - ошибка клиента, остальное - ошибка хранилища файлов
+				if content.err != nil {
+					err = bodyError(content.err)
+				}
+				problem.Write(w, r, err)
+				return
+			}
+			stored = true
+			attachment.Size = content.size
+			attachment.Checksum = hex.EncodeToString(content.hash.Sum(nil))
+		case "checksum":
+			value, err := io.ReadAll(io.LimitReader(part, 128))
+			if err != nil {
+				problem.Write(w, r, bodyError(err))
+				return
+			}
+			req.Checksum = strings.ToLower(strings.TrimSpace(string(value)))
+		}
+	}
+
+	if !stored || validate.Struct(req) != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+	if req.Checksum != "" && req.Checksum != attachment.Checksum {
+		problem.Write(w, r, problem.ErrChecksumMismatch)
+		return
+	}
+
+	// Взаимодействие с бд: проверяем права и привязываем файл к текущей версии тендера или предложения
+	attachment, err = ac.Storage.AddAttachment(r.Context(), attachment, req.Username)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	stored = false
+
+	var resp ResponseDataAttachment
+	resp.Result = attachment
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(result)
+}
+
+// errTooLarge - файл больше MaxSize
+var errTooLarge = errors.New("attachment is too large")
+
+// countingReader считает размер и SHA-256 содержимого и прерывает чтение, как только превышен limit.
+// err - ошибка чтения тела запроса, чтобы отличить её от ошибки записи в хранилище файлов.
+type countingReader struct {
+	r     io.Reader
+	limit int64
+	size  int64
+	hash  hash.Hash
+	err   error
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.size += int64(n)
+	if c.size > c.limit {
+		c.err = errTooLarge
+		return 0, c.err
+	}
+	c.hash.Write(p[:n])
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+	return n, err
+}
+
+// bodyError - ошибка разбора тела запроса: превышение размера или некорректный multipart
+func bodyError(err error) error {
+	var maxBytes *http.MaxBytesError
+	if errors.Is(err, errTooLarge) || errors.As(err, &maxBytes) {
+		return problem.ErrAttachmentTooLarge
+	}
+	return problem.ErrInvalidBody
+}
+
+func contentType(part *multipart.Part) string {
+	if value := part.Header.Get("Content-Type"); value != "" {
+		return value
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,242 @@
+package attachment_test
+
+import (
+	"avito.go/internal/app/services/attachment"
+	"avito.go/internal/blob"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"avito.go/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-256 строки "hello world"
+const helloChecksum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) CanAttach(ctx context.Context, entityType, entityID, username string) error {
+	args := m.Called(ctx, entityType, entityID, username)
+	return args.Error(0)
+}
+
+func (m *MockStorage) AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error) {
+	args := m.Called(ctx, attachment, username)
+	return args.Get(0).(models.Attachment), args.Error(1)
+}
+
+func (m *MockStorage) GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error) {
+	args := m.Called(ctx, entityType, entityID, version, username)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockStorage) GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error) {
+	args := m.Called(ctx, attachmentID, username)
+	return args.Get(0).(models.Attachment), args.Error(1)
+}
+
+func newController(t *testing.T) (*attachment.AttachmentController, *MockStorage, string) {
+	dir := t.TempDir()
+	blobs, err := blob.NewLocal(dir)
+	require.NoError(t, err)
+	mockStorage := &MockStorage{}
+	return &attachment.AttachmentController{Storage: mockStorage, Blobs: blobs, MaxSize: 64}, mockStorage, dir
+}
+
+func uploadRequest(t *testing.T, target, content, checksum string) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "prices.txt")
+	require.NoError(t, err)
+	part.Write([]byte(content))
+	if checksum != "" {
+		require.NoError(t, form.WriteField("checksum", checksum))
+	}
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func storedFiles(t *testing.T, dir string) int {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	return len(entries)
+}
+
+func TestBidAttachmentUpload_Success(t *testing.T) {
+	ac, mockStorage, dir := newController(t)
+
+	mockStorage.On("CanAttach", mock.Anything, models.AuditBid, "b1", "user1").Return(nil)
+	mockStorage.On("AddAttachment", mock.Anything, mock.MatchedBy(func(a models.Attachment) bool {
+		return a.EntityType == models.AuditBid && a.EntityID == "b1" && a.Filename == "prices.txt" &&
+			a.Size == 11 && a.Checksum == helloChecksum && a.ID != ""
+	}), "user1").Return(models.Attachment{ID: "a1", Version: 3, Checksum: helloChecksum}, nil)
+
+	req := uploadRequest(t, "/api/bids/b1/attachments?username=user1", "hello world", strings.ToUpper(helloChecksum))
+	req = mux.SetURLVars(req, map[string]string{"bidId": "b1"})
+	rr := httptest.NewRecorder()
+
+	ac.BidAttachmentUpload(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response attachment.ResponseDataAttachment
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Result.Version)
+	assert.Equal(t, 1, storedFiles(t, dir))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestBidAttachmentUpload_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		checksum   string
+		rightsErr  error
+		storageErr error
+		status     int
+		code       string
+	}{
+		{"too large", strings.Repeat("x", 65), "", nil, nil, http.StatusRequestEntityTooLarge, "attachment_too_large"},
+		{"checksum mismatch", "hello world", strings.Repeat("0", 64), nil, nil, http.StatusBadRequest, "checksum_mismatch"},
+		{"no rights", "hello world", "", storage.ErrRights, nil, http.StatusForbidden, "forbidden"},
+		{"no bid", "hello world", "", storage.ErrNoBid, nil, http.StatusNotFound, "bid_not_found"},
+		{"rights revoked during upload", "hello world", "", nil, storage.ErrRights, http.StatusForbidden, "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac, mockStorage, dir := newController(t)
+			mockStorage.On("CanAttach", mock.Anything, models.AuditBid, "b1", "user1").Return(tt.rightsErr)
+			if tt.storageErr != nil {
+				mockStorage.On("AddAttachment", mock.Anything, mock.Anything, "user1").Return(models.Attachment{}, tt.storageErr)
+			}
+
+			req := uploadRequest(t, "/api/bids/b1/attachments?username=user1", tt.content, tt.checksum)
+			req = mux.SetURLVars(req, map[string]string{"bidId": "b1"})
+			rr := httptest.NewRecorder()
+
+			ac.BidAttachmentUpload(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			var response problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			// Отклонённый файл не остаётся в хранилище
+			assert.Equal(t, 0, storedFiles(t, dir))
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+// failingBody проваливает тест при любом чтении тела запроса
+type failingBody struct {
+	t *testing.T
+}
+
+func (b failingBody) Read(p []byte) (int, error) {
+	b.t.Error("request body must not be read")
+	return 0, io.EOF
+}
+
+func TestTenderAttachmentUpload_RightsBeforeBody(t *testing.T) {
+	ac, mockStorage, dir := newController(t)
+	mockStorage.On("CanAttach", mock.Anything, models.AuditTender, "t1", "user1").Return(storage.ErrRights)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/t1/attachments?username=user1", failingBody{t: t})
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "t1"})
+	rr := httptest.NewRecorder()
+
+	ac.TenderAttachmentUpload(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, 0, storedFiles(t, dir))
+	mockStorage.AssertNotCalled(t, "AddAttachment")
+}
+
+func TestTenderAttachmentUpload_NoFile(t *testing.T) {
+	ac, mockStorage, _ := newController(t)
+	mockStorage.On("CanAttach", mock.Anything, models.AuditTender, "t1", "user1").Return(nil)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("checksum", helloChecksum)
+	form.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/t1/attachments?username=user1", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "t1"})
+	rr := httptest.NewRecorder()
+
+	ac.TenderAttachmentUpload(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockStorage.AssertNotCalled(t, "AddAttachment")
+}
+
+func TestTenderAttachments_Version(t *testing.T) {
+	ac, mockStorage, _ := newController(t)
+
+	expected := []models.Attachment{{ID: "a1", EntityType: models.AuditTender, EntityID: "t1", Version: 2}}
+	mockStorage.On("GetAttachments", mock.Anything, models.AuditTender, "t1", 2, "user1").Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/t1/attachments?username=user1&version=2", nil)
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "t1"})
+	rr := httptest.NewRecorder()
+
+	ac.TenderAttachments(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response attachment.ResponseDataAttachments
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, expected, response.Result)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAttachmentDownload(t *testing.T) {
+	ac, mockStorage, _ := newController(t)
+	require.NoError(t, ac.Blobs.Put(context.Background(), "a1", strings.NewReader("hello world")))
+
+	mockStorage.On("GetAttachment", mock.Anything, "a1", "user1").Return(models.Attachment{
+		ID: "a1", Filename: "prices list.txt", ContentType: "text/plain", Size: 11, Checksum: helloChecksum,
+	}, nil)
+	mockStorage.On("GetAttachment", mock.Anything, "a1", "user2").Return(models.Attachment{}, storage.ErrRights)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/attachments/a1?username=user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"attachmentId": "a1"})
+	rr := httptest.NewRecorder()
+
+	ac.AttachmentDownload(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello world", rr.Body.String())
+	assert.Equal(t, "text/plain", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="prices list.txt"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, helloChecksum, rr.Header().Get(attachment.ChecksumHeader))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/attachments/a1?username=user2", nil)
+	req = mux.SetURLVars(req, map[string]string{"attachmentId": "a1"})
+	rr = httptest.NewRecorder()
+
+	ac.AttachmentDownload(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hello world")
+	mockStorage.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+package attachment
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type RequestDataList struct {
+	EntityID string `schema:"-" validate:"required,max=100"`
+	Version  int    `schema:"version" validate:"gte=0"` // Файлы только этой версии сущности; 0 - всех версий
+	Username string `schema:"username" validate:"required"`
+}
+
+// list отдаёт файлы тендера или предложения в порядке загрузки тем, кому видны предложения тендера
+func (ac *AttachmentController) list(w http.ResponseWriter, r *http.Request, entityType, idVar string) {
+	if r.Method != http.MethodGet {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
+		return
+	}
+
+	var req RequestDataList
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.EntityID = mux.Vars(r)[idVar]
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	attachments, err := ac.Storage.GetAttachments(r.Context(), entityType, req.EntityID, req.Version, req.Username)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataAttachments
+	resp.Result = attachments
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
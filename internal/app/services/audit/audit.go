@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type AuditController struct {
+	Storage storage.AuditStorage
+}
+
+type ErrorResponse struct {
+	Reason    string `json:"reason"`
+	RequestID string `json:"requestId,omitempty"` // Идентификатор запроса из X-Request-ID, по нему ошибку можно найти в логах
+}
+
+// writeStorageError переводит ошибки хранилища в ответы API журнала
+func writeStorageError(w http.ResponseWriter, r *http.Request, err error) {
+	var status int
+	var reason string
+	switch {
+	case errors.Is(err, storage.ErrRights):
+		status, reason = http.StatusForbidden, "Insufficient rights to perform the action."
+	case errors.Is(err, storage.ErrNoUser):
+		status, reason = http.StatusUnauthorized, "The user does not exist or is invalid."
+	case errors.Is(err, cursor.ErrInvalid):
+		status, reason = http.StatusBadRequest, "The request parameters are incorrect."
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	response := ErrorResponse{Reason: reason, RequestID: requestid.FromContext(r.Context())}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataEvents struct {
+	Result     []models.AuditEvent
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы; пуст на последней
+}
+
+type RequestDataEvents struct {
+	OrganizationID string `schema:"-" validate:"required,max=100"`
+	EntityType     string `schema:"entityType" validate:"omitempty,oneof=tender bid"`
+	EntityID       string `schema:"entityId" validate:"max=100"`
+	Action         string `schema:"action" validate:"omitempty,oneof=create edit status rollback decision feedback attach"`
+	Actor          string `schema:"actor" validate:"max=50"`
+	Limit          int    `schema:"limit" validate:"gte=1,lte=100"`
+	Offset         int    `schema:"offset" validate:"gte=0"`
+	Cursor         string `schema:"cursor"` // Курсор next_cursor предыдущей страницы; если задан, offset не учитывается
+	Username       string `schema:"username" validate:"required"`
+}
+
+// AuditEvents отдаёт журнал изменений тендеров и предложений организации, от новых событий к старым
+func (ac *AuditController) AuditEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "Only GET requests are supported", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := RequestDataEvents{
+		Limit:  5,
+		Offset: 0,
+	}
+	decoder := schema.NewDecoder()
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.OrganizationID = mux.Vars(r)["organizationId"]
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	after, errCursor := cursor.Decode(req.Cursor, models.OrderByCreatedAtDesc)
+	if err != nil || errValidate != nil || errCursor != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "The request parameters are incorrect.", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	filter := models.AuditFilter{
+		OrganizationID: req.OrganizationID,
+		EntityType:     req.EntityType,
+		EntityID:       req.EntityID,
+		Action:         req.Action,
+		Actor:          req.Actor,
+	}
+	events, err := ac.Storage.GetAuditEvents(r.Context(), filter, models.Page{Limit: req.Limit, Offset: req.Offset, After: after}, req.Username)
+	if err != nil {
+		writeStorageError(w, r, err)
+		return
+	}
+
+	var resp ResponseDataEvents
+	resp.Result = events
+	if len(events) == req.Limit {
+		resp.NextCursor = cursor.Encode(models.OrderByCreatedAtDesc, models.AuditKey(events[len(events)-1])...)
+	}
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
@@ -0,0 +1,81 @@
+package audit_test
+
+import (
+	"avito.go/internal/app/services/audit"
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) GetAuditEvents(ctx context.Context, filter models.AuditFilter, page models.Page, username string) ([]models.AuditEvent, error) {
+	args := m.Called(ctx, filter, page, username)
+	return args.Get(0).([]models.AuditEvent), args.Error(1)
+}
+
+func newRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return mux.SetURLVars(req, map[string]string{"organizationId": "org1"})
+}
+
+func TestAuditEvents_Success(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ac := audit.AuditController{Storage: mockStorage}
+
+	events := []models.AuditEvent{
+		{ID: "e2", Actor: "user1", Action: models.AuditEdit, EntityType: models.AuditTender, EntityID: "t1", OrganizationID: "org1", VersionBefore: 1, VersionAfter: 2, CreatedAt: time.Date(2024, 9, 24, 12, 0, 0, 0, time.UTC)},
+		{ID: "e1", Actor: "user1", Action: models.AuditCreate, EntityType: models.AuditTender, EntityID: "t1", OrganizationID: "org1", VersionAfter: 1, CreatedAt: time.Date(2024, 9, 24, 11, 0, 0, 0, time.UTC)},
+	}
+	filter := models.AuditFilter{OrganizationID: "org1", EntityType: models.AuditTender, EntityID: "t1"}
+	mockStorage.On("GetAuditEvents", mock.Anything, filter, models.Page{Limit: 2}, "user1").Return(events, nil)
+
+	rr := httptest.NewRecorder()
+	ac.AuditEvents(rr, newRequest("/api/organizations/org1/audit?entityType=tender&entityId=t1&limit=2&username=user1"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response audit.ResponseDataEvents
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Result, 2)
+	assert.Equal(t, 2, response.Result[0].VersionAfter)
+	assert.NotEmpty(t, response.NextCursor)
+
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAuditEvents_InvalidParams(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ac := audit.AuditController{Storage: mockStorage}
+
+	rr := httptest.NewRecorder()
+	ac.AuditEvents(rr, newRequest("/api/organizations/org1/audit?entityType=organization&username=user1"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockStorage.AssertNotCalled(t, "GetAuditEvents")
+}
+
+func TestAuditEvents_Forbidden(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ac := audit.AuditController{Storage: mockStorage}
+
+	mockStorage.On("GetAuditEvents", mock.Anything, models.AuditFilter{OrganizationID: "org1"}, models.Page{Limit: 5}, "user2").Return([]models.AuditEvent{}, storage.ErrRights)
+
+	rr := httptest.NewRecorder()
+	ac.AuditEvents(rr, newRequest("/api/organizations/org1/audit?username=user2"))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockStorage.AssertExpectations(t)
+}
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/storage"
+)
+
+type AuthController struct {
+	Storage storage.AuthStorage
+	Auth    *middleware.Auth
+}
+
+type ErrorResponse struct {
+	Reason    string `json:"reason"`
+	RequestID string `json:"requestId,omitempty"` // Идентификатор запроса из X-Request-ID, по нему ошибку можно найти в логах
+}
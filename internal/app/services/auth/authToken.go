@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"time"
+)
+
+type Token struct {
+	AccessToken string      `json:"accessToken"`
+	TokenType   string      `json:"tokenType"`
+	ExpiresAt   time.Time   `json:"expiresAt"`
+	User        models.User `json:"user"`
+}
+
+type ResponseDataToken struct {
+	Result Token
+}
+
+type RequestDataToken struct {
+	Username string `json:"username" validate:"required,max=50"`
+	Password string `json:"password" validate:"required,max=72"` // bcrypt учитывает не более 72 байт
+}
+
+func (ac *AuthController) IssueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "Only POST requests are supported", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var req RequestDataToken
+	validate := validator.New()
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "The request parameters are incorrect.", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	defer r.Body.Close()
+
+	// токен выдаётся только сотруднику из таблицы employee с заданным паролем
+	user, passwordHash, err := ac.Storage.GetUserCredentials(r.Context(), req.Username)
+	if err != nil && !errors.Is(err, storage.ErrNoUser) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err != nil || passwordHash == "" || bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)) != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+
+		response := ErrorResponse{Reason: "Invalid username or password.", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	token, expiresAt, err := ac.Auth.BuildToken(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resp ResponseDataToken
+	resp.Result = Token{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+		User:        user,
+	}
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(result)
+}
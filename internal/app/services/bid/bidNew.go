@@ -1,11 +1,12 @@
 package bid
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	ID "avito.go/pkg/uuid"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"net/http"
 	"time"
@@ -25,11 +26,7 @@ type RequestDataCreate struct {
 
 func (bc *BidController) CreateBid(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only POST requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPost))
 		return
 	}
 
@@ -37,17 +34,26 @@ func (bc *BidController) CreateBid(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New()
 
 	err := json.NewDecoder(r.Body).Decode(&req)
+	// предложение подаёт сотрудник из токена: от своего имени или от организации, за которую он отвечает
+	userID := middleware.AuthUserID(r, "")
+	if req.AuthorType == "User" && userID != "" {
+		req.AuthorId = userID
+	}
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 	defer r.Body.Close()
 
+	if userID == "" {
+		userID, err = bc.legacyEmployeeID(r, req)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+	}
+
 	bid := models.Bid{
 		ID:          ID.GenerateCorrelationID(),
 		Name:        req.Name,
@@ -62,43 +68,37 @@ func (bc *BidController) CreateBid(w http.ResponseWriter, r *http.Request) {
 
 	// проверка на то, валиден ли юзер
 	// взоимодействие с бд. Создаем новое предложение
-	err = bc.Storage.Add(r.Context(), bid, req.AuthorId, serviceKey)
+	err = bc.Storage.AddBid(r.Context(), bid, userID)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	result, err := json.Marshal(bid)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(int(bid.Version)))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
+
+// legacyEmployeeID - сотрудник, подающий предложение без токена. Предложение пользователя подаёт сам автор,
+// а у предложения организации authorId - идентификатор организации, поэтому сотрудник берётся из параметра username.
+func (bc *BidController) legacyEmployeeID(r *http.Request, req RequestDataCreate) (string, error) {
+	if req.AuthorType == "User" {
+		return req.AuthorId, nil
+	}
+	username := middleware.AuthUsername(r, r.URL.Query().Get("username"))
+	if username == "" {
+		return "", problem.ErrInvalidParameters
+	}
+	employee, err := bc.Employees.GetEmployee(r.Context(), username)
+	if err != nil {
+		return "", err
+	}
+	return employee.ID, nil
+}
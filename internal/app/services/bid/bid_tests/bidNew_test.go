@@ -0,0 +1,119 @@
+package bid_test
+
+import (
+	"avito.go/internal/app/services/bid"
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmployees struct {
+	mock.Mock
+}
+
+func (m *MockEmployees) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	args := m.Called(ctx, prefix, limit, offset)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockEmployees) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockEmployees) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]models.Organization), args.Error(1)
+}
+
+func TestCreateBid_AuthorFromToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorType string
+		authorID   string
+		want       string
+	}{
+		{"user takes id from token", "User", "someone-else", "u1"},
+		{"organization keeps body id", "Organization", "o1", "o1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &MockStorage{}
+			bc := &bid.BidController{Storage: mockStorage}
+			mockStorage.On("AddBid", mock.Anything, mock.MatchedBy(func(b models.Bid) bool {
+				return b.AuthorType == tt.authorType && b.AuthorID == tt.want
+			}), "u1").Return(nil)
+
+			body := `{"name":"Bid","description":"Desc","tenderId":"t1","authorType":"` + tt.authorType + `","authorId":"` + tt.authorID + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/bids/new", strings.NewReader(body))
+			req = req.WithContext(middleware.WithUser(req.Context(), models.User{ID: "u1", Username: "user1"}))
+			rr := httptest.NewRecorder()
+
+			bc.CreateBid(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var response models.Bid
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.want, response.AuthorID)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateBid_Legacy(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorType string
+		authorID   string
+		target     string
+		employeeID string
+	}{
+		{"user submits as author", "User", "u1", "/api/bids/new", "u1"},
+		{"organization submitted by username", "Organization", "o1", "/api/bids/new?username=user1", "u1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &MockStorage{}
+			employees := &MockEmployees{}
+			bc := &bid.BidController{Storage: mockStorage, Employees: employees}
+			if tt.authorType == "Organization" {
+				employees.On("GetEmployee", mock.Anything, "user1").Return(models.User{ID: "u1", Username: "user1"}, nil)
+			}
+			mockStorage.On("AddBid", mock.Anything, mock.MatchedBy(func(b models.Bid) bool {
+				return b.AuthorType == tt.authorType && b.AuthorID == tt.authorID
+			}), tt.employeeID).Return(nil)
+
+			body := `{"name":"Bid","description":"Desc","tenderId":"t1","authorType":"` + tt.authorType + `","authorId":"` + tt.authorID + `"}`
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			bc.CreateBid(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockStorage.AssertExpectations(t)
+			employees.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateBid_LegacyOrganizationWithoutUsername(t *testing.T) {
+	mockStorage := &MockStorage{}
+	bc := &bid.BidController{Storage: mockStorage, Employees: &MockEmployees{}}
+
+	body := `{"name":"Bid","description":"Desc","tenderId":"t1","authorType":"Organization","authorId":"o1"}`
+	rr := httptest.NewRecorder()
+	bc.CreateBid(rr, httptest.NewRequest(http.MethodPost, "/api/bids/new", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockStorage.AssertNotCalled(t, "AddBid")
+}
@@ -10,39 +10,44 @@ type MockStorage struct {
 	mock.Mock
 }
 
-func (m *MockStorage) GetMy(ctx context.Context, limit, offset int, username string, key int) (interface{}, error) {
+func (m *MockStorage) AddBid(ctx context.Context, bid models.Bid, userID string) error {
+	args := m.Called(ctx, bid, userID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetMyBids(ctx context.Context, page models.Page, username string) ([]models.Bid, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockStorage) GetStatus(ctx context.Context, Id, username string, key int) (string, error) {
+func (m *MockStorage) GetBidStatus(ctx context.Context, bidID, username string) (string, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockStorage) UpdateStatus(ctx context.Context, Id, status, username string, key int) (interface{}, error) {
+func (m *MockStorage) UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockStorage) RollbackVersion(ctx context.Context, Id, version, username string, key int) (interface{}, error) {
+func (m *MockStorage) RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockStorage) GetTenderBids(ctx context.Context, tenderId, username string, limit, offset int) ([]models.Bid, error) {
+func (m *MockStorage) GetTenderBids(ctx context.Context, tenderId, username string, page models.Page) ([]models.Bid, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
 func (m *MockStorage) SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, bidId, decision, username)
+	return args.Get(0).(models.Bid), args.Error(1)
 }
 
-func (m *MockStorage) EditBid(ctx context.Context, bidId, username, bidName, description, status string) (models.Bid, error) {
-	//TODO implement me
-	panic("implement me")
+func (m *MockStorage) EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error) {
+	args := m.Called(ctx, bidId, username, bidName, description, status, expectedVersion)
+	return args.Get(0).(models.Bid), args.Error(1)
 }
 
 func (m *MockStorage) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) {
@@ -50,7 +55,17 @@ func (m *MockStorage) AddFeedbackBid(ctx context.Context, bidId string, bidFeedb
 	panic("implement me")
 }
 
-func (m *MockStorage) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, limit, offset int) ([]models.FeedBack, error) {
+func (m *MockStorage) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, page models.Page) ([]models.FeedBack, error) {
 	//TODO implement me
 	panic("implement me")
 }
+
+func (m *MockStorage) GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error) {
+	args := m.Called(ctx, bidID, username, limit, offset)
+	return args.Get(0).([]models.Bid), args.Error(1)
+}
+
+func (m *MockStorage) DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error) {
+	args := m.Called(ctx, bidID, username, from, to)
+	return args.Get(0).(models.VersionDiff), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package bid_test
+
+import (
+	"avito.go/internal/app/services/bid"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBidEdit_WithoutIfMatch(t *testing.T) {
+	mockStorage := &MockStorage{}
+	bc := &bid.BidController{Storage: mockStorage}
+	// Без If-Match версия не проверяется: в хранилище передаётся 0
+	mockStorage.On("EditBid", mock.Anything, "1", "user1", "Updated Bid", "", "", 0).Return(models.Bid{ID: "1", Name: "Updated Bid", Version: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/bids/1/edit?username=user1", strings.NewReader(`{"name":"Updated Bid"}`))
+	req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+	rr := httptest.NewRecorder()
+
+	bc.BidEdit(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestBidEdit_IfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		code    string
+	}{
+		{"weak tag", `W/"2"`, http.StatusPreconditionFailed, "version_mismatch"},
+		{"malformed tag", `2`, http.StatusBadRequest, "invalid_if_match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &MockStorage{}
+			bc := &bid.BidController{Storage: mockStorage}
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/bids/1/edit?username=user1", strings.NewReader(`{"name":"Updated Bid"}`))
+			req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+			req.Header.Set("If-Match", tt.ifMatch)
+			rr := httptest.NewRecorder()
+
+			bc.BidEdit(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+			var response problem.Problem
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			// До хранилища запрос не доходит
+			mockStorage.AssertNotCalled(t, "EditBid")
+		})
+	}
+}
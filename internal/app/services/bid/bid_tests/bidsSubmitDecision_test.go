@@ -0,0 +1,58 @@
+package bid_test
+
+import (
+	"avito.go/internal/app/services/bid"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"avito.go/internal/storage"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBidSubmitDecision_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"no bid", storage.ErrNoBid, http.StatusNotFound, "bid_not_found", "The bid does not exist."},
+		{"rights", storage.ErrRights, http.StatusForbidden, "forbidden", "Insufficient rights to perform the action."},
+		{"decision made", storage.ErrDecisionMade, http.StatusConflict, "decision_made", "The decision has already been made."},
+		{"not published", storage.ErrInvalidTransition, http.StatusConflict, "invalid_transition", "The status transition is not allowed."},
+		{"unknown", errors.New("connection reset"), http.StatusInternalServerError, "internal", "Internal server error."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &MockStorage{}
+			bc := &bid.BidController{Storage: mockStorage}
+			mockStorage.On("SubmitDecisionBid", mock.Anything, "1", "Approved", "user1").Return(models.Bid{}, tt.err)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/bids/1/submit_decision?decision=Approved&username=user1", nil)
+			req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+			rr := httptest.NewRecorder()
+
+			bc.BidSubmitDecision(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+
+			var response problem.Problem
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.status, response.Status)
+			assert.Equal(t, tt.code, response.Code)
+			assert.Equal(t, tt.detail, response.Detail)
+			assert.NotContains(t, rr.Body.String(), "connection reset")
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
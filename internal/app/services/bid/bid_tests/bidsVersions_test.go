@@ -0,0 +1,85 @@
+package bid_test
+
+import (
+	"avito.go/internal/app/services/bid"
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBidVersions_Success(t *testing.T) {
+	mockStorage := &MockStorage{}
+	bc := &bid.BidController{
+		Storage: mockStorage,
+	}
+
+	versions := []models.Bid{
+		{ID: "1", Name: "Bid 1 version 2", Version: 2},
+		{ID: "1", Name: "Bid 1", Version: 1},
+	}
+	mockStorage.On("GetBidVersions", mock.Anything, "1", "user1", 5, 0).Return(versions, nil)
+
+	req := httptest.NewRequest("GET", "/api/bids/1/versions?username=user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+	rr := httptest.NewRecorder()
+
+	bc.BidVersions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response bid.ResponseDataVersions
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, versions, response.Result)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestBidDiff_Success(t *testing.T) {
+	mockStorage := &MockStorage{}
+	bc := &bid.BidController{
+		Storage: mockStorage,
+	}
+
+	diff := models.DiffBids(
+		models.Bid{Name: "Bid 1", Status: "Created", Version: 1},
+		models.Bid{Name: "Bid 1", Status: "Published", Version: 2},
+	)
+	mockStorage.On("DiffBidVersions", mock.Anything, "1", "user1", 1, 2).Return(diff, nil)
+
+	req := httptest.NewRequest("GET", "/api/bids/1/diff?username=user1&from=1&to=2", nil)
+	req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+	rr := httptest.NewRecorder()
+
+	bc.BidDiff(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response bid.ResponseDataDiff
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, []models.FieldChange{{Field: "status", From: "Created", To: "Published"}}, response.Result.Changes)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestBidDiff_NoVersion(t *testing.T) {
+	mockStorage := &MockStorage{}
+	bc := &bid.BidController{
+		Storage: mockStorage,
+	}
+
+	mockStorage.On("DiffBidVersions", mock.Anything, "1", "user1", 1, 7).Return(models.VersionDiff{}, storage.ErrNoVersion)
+
+	req := httptest.NewRequest("GET", "/api/bids/1/diff?username=user1&from=1&to=7", nil)
+	req = mux.SetURLVars(req, map[string]string{"bidId": "1"})
+	rr := httptest.NewRecorder()
+
+	bc.BidDiff(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockStorage.AssertExpectations(t)
+}
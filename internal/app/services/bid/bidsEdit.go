@@ -1,10 +1,11 @@
 package bid
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -29,20 +30,12 @@ func (bc *BidController) BidEdit(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidID, ok := vars["bidId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	if r.Method != http.MethodPatch {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only PATCH requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPatch))
 		return
 	}
 
@@ -54,13 +47,17 @@ func (bc *BidController) BidEdit(w http.ResponseWriter, r *http.Request) {
 	params.BidID = bidID
 
 	err := decoder.Decode(&params, r.URL.Query())
+	params.Username = middleware.AuthUsername(r, params.Username)
 	errValidate := validate.Struct(params)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
 
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	// If-Match с версией сущности защищает от перезаписи чужих изменений
+	expectedVersion, err := etag.Parse(r.Header.Get("If-Match"))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -69,11 +66,7 @@ func (bc *BidController) BidEdit(w http.ResponseWriter, r *http.Request) {
 	err = json.NewDecoder(r.Body).Decode(&req)
 	errValidate = validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request body are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidBody)
 		return
 	}
 	defer r.Body.Close()
@@ -82,45 +75,22 @@ func (bc *BidController) BidEdit(w http.ResponseWriter, r *http.Request) {
 	// изменение параметров существующего предложения
 	// пользователь не существует или некорректен. - 401
 
-	bid, err := bc.Storage.EditBid(r.Context(), params.BidID, params.Username, req.BidName, req.TenderDescription, "")
+	bid, err := bc.Storage.EditBid(r.Context(), params.BidID, params.Username, req.BidName, req.TenderDescription, "", expectedVersion)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	var resp ResponseDataEdit
 	resp.Result = bid
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(int(bid.Version)))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
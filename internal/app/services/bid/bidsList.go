@@ -1,10 +1,11 @@
 package bid
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/cursor"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -12,7 +13,8 @@ import (
 )
 
 type ResponseDataList struct {
-	Result []models.Bid
+	Result     []models.Bid
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы; пуст на последней
 }
 
 type RequestDataList struct {
@@ -20,25 +22,18 @@ type RequestDataList struct {
 	Username string `schema:"username" validate:"required"`
 	Limit    int    `schema:"limit" validate:"gte=1,lte=100"` // Параметр limit (min 1, max 100)
 	Offset   int    `schema:"offset" validate:"gte=0"`        // Параметр offset (минимум 0)
+	Cursor   string `schema:"cursor"`                         // Курсор next_cursor предыдущей страницы; если задан, offset не учитывается
 }
 
 func (bc *BidController) BidsTenderList(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderID, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 	if r.Method != http.MethodGet {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only GET requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
 		return
 	}
 
@@ -52,13 +47,11 @@ func (bc *BidController) BidsTenderList(w http.ResponseWriter, r *http.Request)
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
-	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	after, errCursor := cursor.Decode(req.Cursor, models.OrderByName)
+	if err != nil || errValidate != nil || errCursor != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -68,51 +61,23 @@ func (bc *BidController) BidsTenderList(w http.ResponseWriter, r *http.Request)
 	//
 	//Если фильтры не заданы, возвращаются все тендеры.
 
-	bids, err = bc.Storage.GetTenderBids(r.Context(), req.TenderID, req.Username, req.Limit, req.Offset)
+	bids, err = bc.Storage.GetTenderBids(r.Context(), req.TenderID, req.Username, models.Page{Limit: req.Limit, Offset: req.Offset, After: after})
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoBid):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The bid does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 	//TODO: отсортировать по алфавиту по названию
 	//TODO: добавить логику обработки данных, учесть лимит и сдвиг
 
 	var resp ResponseDataList
 	resp.Result = bids
+	if len(bids) == req.Limit {
+		resp.NextCursor = cursor.Encode(models.OrderByName, models.BidKey(bids[len(bids)-1])...)
+	}
 
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
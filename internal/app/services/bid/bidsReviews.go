@@ -1,10 +1,11 @@
 package bid
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/cursor"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -12,7 +13,8 @@ import (
 )
 
 type ResponseDataReviews struct {
-	Result []models.FeedBack
+	Result     []models.FeedBack
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы; пуст на последней
 }
 
 type RequestDataReviews struct {
@@ -21,24 +23,18 @@ type RequestDataReviews struct {
 	RequesterUsername string `schema:"requesterUsername" validate:"required,max=100"`
 	Limit             int    `schema:"limit" validate:"gte=1,lte=100"`
 	Offset            int    `schema:"offset" validate:"gte=0"`
+	Cursor            string `schema:"cursor"` // Курсор next_cursor предыдущей страницы; если задан, offset не учитывается
 }
 
 func (bc *BidController) BidsReviews(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderID, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 	if r.Method != http.MethodGet {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only GET requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
 		return
 	}
 
@@ -51,65 +47,28 @@ func (bc *BidController) BidsReviews(w http.ResponseWriter, r *http.Request) {
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.TenderID = tenderID
+	req.AuthorUsername = middleware.AuthUsername(r, req.AuthorUsername)
 	errValidate := validate.Struct(req)
-	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	after, errCursor := cursor.Decode(req.Cursor, models.OrderByCreatedAt)
+	if err != nil || errValidate != nil || errCursor != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
-	feedbacks, err := bc.Storage.GetFeedback(r.Context(), req.TenderID, req.AuthorUsername, req.RequesterUsername, req.Limit, req.Offset)
+	feedbacks, err := bc.Storage.GetFeedback(r.Context(), req.TenderID, req.AuthorUsername, req.RequesterUsername, models.Page{Limit: req.Limit, Offset: req.Offset, After: after})
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoBid):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The bid does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoReviews):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "Reviews does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	var resp ResponseDataReviews
 	resp.Result = feedbacks
+	if len(feedbacks) == req.Limit {
+		resp.NextCursor = cursor.Encode(models.OrderByCreatedAt, models.FeedbackKey(feedbacks[len(feedbacks)-1])...)
+	}
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
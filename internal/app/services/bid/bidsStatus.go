@@ -1,11 +1,11 @@
 package bid
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -23,7 +23,7 @@ type RequestDataStatus struct {
 
 type RequestDataUpdateStatus struct {
 	BidID    string `schema:"bidId" validate:"required,max=100"`
-	Status   string `schema:"status" validate:"required,oneof=Created Published Closed"`
+	Status   string `schema:"status" validate:"required,oneof=Created Published Canceled"` // Approved и Rejected выставляются через submit_decision
 	Username string `schema:"username" validate:"required"`
 }
 
@@ -31,20 +31,12 @@ func (bc *BidController) BidStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidID, ok := vars["bidId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	if r.Method != http.MethodGet {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only GET requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
 		return
 	}
 
@@ -54,46 +46,19 @@ func (bc *BidController) BidStatus(w http.ResponseWriter, r *http.Request) {
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.BidID = bidID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	// получаем актуальное значение статуса тендера
 	// если пользователь не существует или некорректен - 401
-	status, err := bc.Storage.GetStatus(r.Context(), req.BidID, req.Username, serviceKey)
+	status, err := bc.Storage.GetBidStatus(r.Context(), req.BidID, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoBid):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -105,20 +70,12 @@ func (bc *BidController) BidUpdateStatus(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	bidID, ok := vars["bidId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	if r.Method != http.MethodPut {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only PUT requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPut))
 		return
 	}
 
@@ -128,13 +85,10 @@ func (bc *BidController) BidUpdateStatus(w http.ResponseWriter, r *http.Request)
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.BidID = bidID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -143,38 +97,9 @@ func (bc *BidController) BidUpdateStatus(w http.ResponseWriter, r *http.Request)
 	// если недостаточно прав для выполнения действия - 403
 	// если тендер не найден - 404
 
-	bidInterface, err := bc.Storage.UpdateStatus(r.Context(), req.BidID, req.Status, req.Username, serviceKey)
+	bid, err := bc.Storage.UpdateBidStatus(r.Context(), req.BidID, req.Status, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoBid):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-	bid, ok := bidInterface.(models.Bid)
-	if !ok {
-		fmt.Println("Failed to convert interface to []models.Tender")
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -182,11 +107,12 @@ func (bc *BidController) BidUpdateStatus(w http.ResponseWriter, r *http.Request)
 	resp.Result = bid
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(int(bid.Version)))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
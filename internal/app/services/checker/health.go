@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"avito.go/pkg/logger"
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// Состояния проверок
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+)
+
+// Причины деградации в ответе. Проба доступна без авторизации, поэтому текст ошибок только пишется в лог
+const (
+	errDatabaseUnavailable = "database is unavailable"
+	errSchemaUnknown       = "schema version is unknown"
+	errMigrationsPending   = "migrations are not applied"
+)
+
+// readyTimeout ограничивает время проверки зависимостей, чтобы зависшая база не держала пробу
+const readyTimeout = 2 * time.Second
+
+type HealthResponse struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks,omitempty"`
+}
+
+// Check - результат проверки одной зависимости
+type Check struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`   // Фиксированная причина деградации, подробности - в логе
+	Version int64  `json:"version,omitempty"` // Для migrations: применённая версия схемы
+	Latest  int64  `json:"latest,omitempty"`  // Для migrations: версия последней встроенной миграции
+}
+
+// HealthLive отвечает 200, пока процесс способен обрабатывать запросы; зависимости не проверяет
+func (cc *CheckerController) HealthLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, HealthResponse{Status: StatusOK})
+}
+
+// HealthReady проверяет соединение с базой и версию схемы; пока миграции не применены, отвечает 503
+func (cc *CheckerController) HealthReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+	log := logger.FromContext(r.Context())
+
+	resp := HealthResponse{Status: StatusOK, Checks: map[string]Check{}}
+
+	database := Check{Status: StatusOK}
+	if err := cc.Storage.Ping(ctx); err != nil {
+		log.Error("readiness check failed", zap.String("check", "database"), zap.Error(err))
+		database = Check{Status: StatusDegraded, Error: errDatabaseUnavailable}
+	}
+	resp.Checks["database"] = database
+
+	migrations := Check{Status: StatusOK}
+	if database.Status != StatusOK {
+		migrations = Check{Status: StatusDegraded, Error: errDatabaseUnavailable}
+	} else if current, latest, err := cc.Storage.SchemaVersion(ctx); err != nil {
+		log.Error("readiness check failed", zap.String("check", "migrations"), zap.Error(err))
+		migrations = Check{Status: StatusDegraded, Error: errSchemaUnknown, Latest: latest}
+	} else if current < latest {
+		migrations = Check{Status: StatusDegraded, Error: errMigrationsPending, Version: current, Latest: latest}
+	} else {
+		migrations.Version, migrations.Latest = current, latest
+	}
+	resp.Checks["migrations"] = migrations
+
+	for _, check := range resp.Checks {
+		if check.Status != StatusOK {
+			resp.Status = StatusDegraded
+		}
+	}
+	writeHealth(w, resp)
+}
+
+func writeHealth(w http.ResponseWriter, resp HealthResponse) {
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if resp.Status != StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(result)
+}
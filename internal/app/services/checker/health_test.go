@@ -0,0 +1,99 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SchemaVersion(ctx context.Context) (int64, int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func serveReady(t *testing.T, storage *MockStorage) (*httptest.ResponseRecorder, HealthResponse) {
+	cc := &CheckerController{Storage: storage}
+	rr := httptest.NewRecorder()
+	cc.HealthReady(rr, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+	var response HealthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr, response
+}
+
+func TestHealthLive(t *testing.T) {
+	cc := &CheckerController{}
+	rr := httptest.NewRecorder()
+	cc.HealthLive(rr, httptest.NewRequest(http.MethodGet, "/api/health/live", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestHealthReady_OK(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("Ping", mock.Anything).Return(nil)
+	storage.On("SchemaVersion", mock.Anything).Return(int64(20240924120000), int64(20240924120000), nil)
+
+	rr, response := serveReady(t, storage)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, StatusOK, response.Status)
+	assert.Equal(t, int64(20240924120000), response.Checks["migrations"].Version)
+}
+
+func TestHealthReady_PendingMigrations(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("Ping", mock.Anything).Return(nil)
+	storage.On("SchemaVersion", mock.Anything).Return(int64(0), int64(20240924120000), nil)
+
+	rr, response := serveReady(t, storage)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, StatusDegraded, response.Status)
+	assert.Equal(t, StatusOK, response.Checks["database"].Status)
+	assert.Equal(t, StatusDegraded, response.Checks["migrations"].Status)
+	assert.Equal(t, errMigrationsPending, response.Checks["migrations"].Error)
+}
+
+func TestHealthReady_SchemaVersionFailed(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("Ping", mock.Anything).Return(nil)
+	storage.On("SchemaVersion", mock.Anything).Return(int64(0), int64(20240924120000), errors.New(`permission denied for table goose_db_version`))
+
+	rr, response := serveReady(t, storage)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, errSchemaUnknown, response.Checks["migrations"].Error)
+	assert.NotContains(t, rr.Body.String(), "goose_db_version")
+}
+
+func TestHealthReady_DatabaseDown(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("Ping", mock.Anything).Return(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+
+	rr, response := serveReady(t, storage)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	// Текст ошибки с адресом базы наружу не попадает
+	assert.Equal(t, errDatabaseUnavailable, response.Checks["database"].Error)
+	assert.NotContains(t, rr.Body.String(), "10.0.0.5")
+	assert.Equal(t, StatusDegraded, response.Checks["migrations"].Status)
+	storage.AssertNotCalled(t, "SchemaVersion", mock.Anything)
+}
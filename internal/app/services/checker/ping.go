@@ -1,6 +1,7 @@
 package checker
 
 import (
+	"avito.go/internal/storage"
 	"encoding/json"
 	"net/http"
 )
@@ -10,6 +11,7 @@ type Response struct {
 }
 
 type CheckerController struct {
+	Storage storage.HealthStorage
 }
 
 func (cc *CheckerController) CheckServer(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,14 @@
+package employee
+
+import (
+	"avito.go/internal/storage"
+)
+
+type EmployeeController struct {
+	Storage storage.EmployeeStorage
+}
+
+type ErrorResponse struct {
+	Reason    string `json:"reason"`
+	RequestID string `json:"requestId,omitempty"` // Идентификатор запроса из X-Request-ID, по нему ошибку можно найти в логах
+}
@@ -0,0 +1,104 @@
+package employee
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type ResponseDataProfile struct {
+	Result models.User
+}
+
+type ResponseDataOrganizations struct {
+	Result []models.Organization
+}
+
+func (ec *EmployeeController) EmployeeProfile(w http.ResponseWriter, r *http.Request) {
+	username, ok := usernameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := ec.Storage.GetEmployee(r.Context(), username)
+	if err != nil {
+		writeStorageError(w, r, err)
+		return
+	}
+
+	var resp ResponseDataProfile
+	resp.Result = user
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+func (ec *EmployeeController) EmployeeOrganizations(w http.ResponseWriter, r *http.Request) {
+	username, ok := usernameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	organizations, err := ec.Storage.GetEmployeeOrganizations(r.Context(), username)
+	if err != nil {
+		writeStorageError(w, r, err)
+		return
+	}
+
+	var resp ResponseDataOrganizations
+	resp.Result = organizations
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+func usernameFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "Only GET requests are supported", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return "", false
+	}
+
+	username, ok := mux.Vars(r)["username"]
+	if !ok || username == "" || len(username) > 50 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "The request parameters are incorrect.", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return "", false
+	}
+	return username, true
+}
+
+func writeStorageError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, storage.ErrNoUser) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+
+	response := ErrorResponse{Reason: "The employee does not exist.", RequestID: requestid.FromContext(r.Context())}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,91 @@
+package employee_test
+
+import (
+	"avito.go/internal/app/services/employee"
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	args := m.Called(ctx, prefix, limit, offset)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockStorage) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockStorage) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]models.Organization), args.Error(1)
+}
+
+func TestEmployeesList_Prefix(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ec := employee.EmployeeController{Storage: mockStorage}
+
+	users := []models.User{{ID: "1", Username: "user1"}, {ID: "2", Username: "user2"}}
+	mockStorage.On("GetEmployees", mock.Anything, "us", 10, 0).Return(users, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/employees?prefix=us&limit=10", nil)
+	rr := httptest.NewRecorder()
+
+	ec.EmployeesList(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response employee.ResponseDataList
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, users, response.Result)
+}
+
+func TestEmployeeProfile_NotFound(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ec := employee.EmployeeController{Storage: mockStorage}
+
+	mockStorage.On("GetEmployee", mock.Anything, "ghost").Return(models.User{}, storage.ErrNoUser)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/employees/ghost", nil)
+	req = mux.SetURLVars(req, map[string]string{"username": "ghost"})
+	rr := httptest.NewRecorder()
+
+	ec.EmployeeProfile(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestEmployeeOrganizations_Success(t *testing.T) {
+	mockStorage := new(MockStorage)
+	ec := employee.EmployeeController{Storage: mockStorage}
+
+	organizations := []models.Organization{{ID: "org1", Name: "Org"}}
+	mockStorage.On("GetEmployeeOrganizations", mock.Anything, "user1").Return(organizations, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/employees/user1/organizations", nil)
+	req = mux.SetURLVars(req, map[string]string{"username": "user1"})
+	rr := httptest.NewRecorder()
+
+	ec.EmployeeOrganizations(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response employee.ResponseDataOrganizations
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Org", response.Result[0].Name)
+}
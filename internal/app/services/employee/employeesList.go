@@ -0,0 +1,69 @@
+package employee
+
+import (
+	"avito.go/internal/models"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataList struct {
+	Result []models.User
+}
+
+type RequestDataList struct {
+	Prefix string `schema:"prefix" validate:"max=50"`       // Начало username для поиска
+	Limit  int    `schema:"limit" validate:"gte=1,lte=100"` // Параметр limit (min 1, max 100)
+	Offset int    `schema:"offset" validate:"gte=0"`        // Параметр offset (минимум 0)
+}
+
+func (ec *EmployeeController) EmployeesList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "Only GET requests are supported", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := RequestDataList{
+		Limit:  5,
+		Offset: 0,
+	}
+
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		response := ErrorResponse{Reason: "The request parameters are incorrect.", RequestID: requestid.FromContext(r.Context())}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	users, err := ec.Storage.GetEmployees(r.Context(), req.Prefix, req.Limit, req.Offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp ResponseDataList
+	resp.Result = users
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
@@ -0,0 +1,9 @@
+package organization
+
+import (
+	"avito.go/internal/storage"
+)
+
+type OrganizationController struct {
+	Storage storage.OrganizationStorage
+}
@@ -0,0 +1,51 @@
+package organization
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type RequestDataDelete struct {
+	OrganizationID string `schema:"organizationId" validate:"required,max=100"`
+	Username       string `schema:"username" validate:"required"`
+}
+
+func (oc *OrganizationController) OrganizationDelete(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationID, ok := vars["organizationId"]
+	if !ok {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodDelete))
+		return
+	}
+
+	var req RequestDataDelete
+	decoder := schema.NewDecoder()
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.OrganizationID = organizationID
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	// удаление организации каскадно удаляет её тендеры и ответственных
+	err = oc.Storage.DeleteOrganization(r.Context(), req.OrganizationID, req.Username)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package organization
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataEdit struct {
+	Result models.Organization
+}
+
+type RequestParamsEdit struct {
+	OrganizationID string `schema:"organizationId" validate:"required,max=100"`
+	Username       string `schema:"username" validate:"required"`
+}
+
+type RequestBodyEdit struct {
+	Name        string `json:"name" validate:"max=100"`
+	Description string `json:"description" validate:"max=500"`
+	Type        string `json:"type" validate:"omitempty,oneof=IE LLC JSC"`
+}
+
+func (oc *OrganizationController) OrganizationEdit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationID, ok := vars["organizationId"]
+	if !ok {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	if r.Method != http.MethodPatch {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPatch))
+		return
+	}
+	decoder := schema.NewDecoder()
+	validate := validator.New()
+
+	var params RequestParamsEdit
+	err := decoder.Decode(&params, r.URL.Query())
+	params.OrganizationID = organizationID
+	params.Username = middleware.AuthUsername(r, params.Username)
+	errValidate := validate.Struct(params)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	var req RequestBodyEdit
+	err = json.NewDecoder(r.Body).Decode(&req)
+	defer r.Body.Close()
+	errValidate = validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+
+	organization, err := oc.Storage.EditOrganization(r.Context(), params.OrganizationID, params.Username, req.Name, req.Description, req.Type)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataEdit
+	resp.Result = organization
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
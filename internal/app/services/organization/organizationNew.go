@@ -0,0 +1,70 @@
+package organization
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	ID "avito.go/pkg/uuid"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+)
+
+type ResponseDataCreate struct {
+	Result models.Organization
+}
+
+type RequestDataCreate struct {
+	Name            string `json:"name" validate:"required,max=100"`           // Полное название организации
+	Description     string `json:"description" validate:"max=500"`             // Описание организации
+	Type            string `json:"type" validate:"omitempty,oneof=IE LLC JSC"` // Организационно-правовая форма
+	CreatorUsername string `json:"creatorUsername" validate:"required"`        // Сотрудник, который станет первым ответственным
+}
+
+func (oc *OrganizationController) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPost))
+		return
+	}
+
+	var req RequestDataCreate
+	validate := validator.New()
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	req.CreatorUsername = middleware.AuthUsername(r, req.CreatorUsername)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+	defer r.Body.Close()
+
+	now := time.Now()
+	organization := models.Organization{
+		ID:          ID.GenerateCorrelationID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = oc.Storage.CreateOrganization(r.Context(), organization, req.CreatorUsername)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataCreate
+	resp.Result = organization
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
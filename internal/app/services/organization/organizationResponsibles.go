@@ -0,0 +1,102 @@
+package organization
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataResponsibles struct {
+	Result []models.User
+}
+
+type RequestDataResponsible struct {
+	OrganizationID   string `schema:"organizationId" validate:"required,max=100"`
+	EmployeeUsername string `schema:"employeeUsername" validate:"required,max=50"`
+	Username         string `schema:"username" validate:"required"`
+}
+
+func (oc *OrganizationController) OrganizationResponsibles(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationID, ok := vars["organizationId"]
+	if !ok || len(organizationID) > 100 {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	users, err := oc.Storage.GetOrganizationResponsibles(r.Context(), organizationID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataResponsibles
+	resp.Result = users
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+func (oc *OrganizationController) OrganizationAddResponsible(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeResponsibleRequest(w, r, http.MethodPut)
+	if !ok {
+		return
+	}
+
+	err := oc.Storage.AddOrganizationResponsible(r.Context(), req.OrganizationID, req.Username, req.EmployeeUsername)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	oc.OrganizationResponsibles(w, r)
+}
+
+func (oc *OrganizationController) OrganizationRemoveResponsible(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeResponsibleRequest(w, r, http.MethodDelete)
+	if !ok {
+		return
+	}
+
+	err := oc.Storage.RemoveOrganizationResponsible(r.Context(), req.OrganizationID, req.Username, req.EmployeeUsername)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	oc.OrganizationResponsibles(w, r)
+}
+
+func decodeResponsibleRequest(w http.ResponseWriter, r *http.Request, method string) (RequestDataResponsible, bool) {
+	var req RequestDataResponsible
+
+	if r.Method != method {
+		problem.Write(w, r, problem.MethodNotSupported(method))
+		return req, false
+	}
+
+	vars := mux.Vars(r)
+	decoder := schema.NewDecoder()
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.OrganizationID = vars["organizationId"]
+	req.EmployeeUsername = vars["employeeUsername"]
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return req, false
+	}
+	return req, true
+}
@@ -0,0 +1,149 @@
+package organization_test
+
+import (
+	"avito.go/internal/app/services/organization"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"avito.go/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) CreateOrganization(ctx context.Context, organization models.Organization, username string) error {
+	args := m.Called(ctx, organization, username)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]models.Organization), args.Error(1)
+}
+
+func (m *MockStorage) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	args := m.Called(ctx, organizationID)
+	return args.Get(0).(models.Organization), args.Error(1)
+}
+
+func (m *MockStorage) EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error) {
+	args := m.Called(ctx, organizationID, username, name, description, organizationType)
+	return args.Get(0).(models.Organization), args.Error(1)
+}
+
+func (m *MockStorage) DeleteOrganization(ctx context.Context, organizationID, username string) error {
+	args := m.Called(ctx, organizationID, username)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	args := m.Called(ctx, organizationID)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockStorage) AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	args := m.Called(ctx, organizationID, username, employeeUsername)
+	return args.Error(0)
+}
+
+func (m *MockStorage) RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	args := m.Called(ctx, organizationID, username, employeeUsername)
+	return args.Error(0)
+}
+
+func TestCreateOrganization_Success(t *testing.T) {
+	mockStorage := new(MockStorage)
+	oc := organization.OrganizationController{Storage: mockStorage}
+
+	reqBody := `{"name": "Org", "description": "description", "type": "LLC", "creatorUsername": "user1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations/new", bytes.NewBufferString(reqBody))
+	rr := httptest.NewRecorder()
+
+	mockStorage.On("CreateOrganization", mock.Anything, mock.AnythingOfType("models.Organization"), "user1").Return(nil)
+
+	oc.CreateOrganization(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response organization.ResponseDataCreate
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Org", response.Result.Name)
+	assert.Equal(t, "LLC", response.Result.Type)
+	assert.NotEmpty(t, response.Result.ID)
+}
+
+func TestOrganizationEdit_Forbidden(t *testing.T) {
+	mockStorage := new(MockStorage)
+	oc := organization.OrganizationController{Storage: mockStorage}
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/organizations/1/edit?username=user2", bytes.NewBufferString(`{"name": "New"}`))
+	req = mux.SetURLVars(req, map[string]string{"organizationId": "1"})
+	rr := httptest.NewRecorder()
+
+	mockStorage.On("EditOrganization", mock.Anything, "1", "user2", "New", "", "").Return(models.Organization{}, storage.ErrRights)
+
+	oc.OrganizationEdit(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestOrganizationRemoveResponsible_Last(t *testing.T) {
+	mockStorage := new(MockStorage)
+	oc := organization.OrganizationController{Storage: mockStorage}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/organizations/1/responsibles/user1?username=user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"organizationId": "1", "employeeUsername": "user1"})
+	rr := httptest.NewRecorder()
+
+	mockStorage.On("RemoveOrganizationResponsible", mock.Anything, "1", "user1", "user1").Return(storage.ErrLastResponsible)
+
+	oc.OrganizationRemoveResponsible(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestOrganizationRemoveResponsible_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not responsible", storage.ErrNotResponsible, http.StatusNotFound, "responsible_not_found"},
+		{"unknown", errors.New("pq: relation does not exist"), http.StatusInternalServerError, "internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(MockStorage)
+			oc := organization.OrganizationController{Storage: mockStorage}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/organizations/1/responsibles/user2?username=user1", nil)
+			req = mux.SetURLVars(req, map[string]string{"organizationId": "1", "employeeUsername": "user2"})
+			rr := httptest.NewRecorder()
+
+			mockStorage.On("RemoveOrganizationResponsible", mock.Anything, "1", "user1", "user2").Return(tt.err)
+
+			oc.OrganizationRemoveResponsible(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+			var response problem.Problem
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			assert.NotContains(t, rr.Body.String(), "relation does not exist")
+		})
+	}
+}
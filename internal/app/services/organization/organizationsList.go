@@ -0,0 +1,92 @@
+package organization
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataList struct {
+	Result []models.Organization
+}
+
+type ResponseDataGet struct {
+	Result models.Organization
+}
+
+type RequestDataList struct {
+	Limit  int `schema:"limit" validate:"gte=1,lte=100"` // Параметр limit (min 1, max 100)
+	Offset int `schema:"offset" validate:"gte=0"`        // Параметр offset (минимум 0)
+}
+
+func (oc *OrganizationController) OrganizationsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
+		return
+	}
+
+	req := RequestDataList{
+		Limit:  5,
+		Offset: 0,
+	}
+
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	organizations, err := oc.Storage.GetOrganizations(r.Context(), req.Limit, req.Offset)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataList
+	resp.Result = organizations
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+func (oc *OrganizationController) OrganizationGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationID, ok := vars["organizationId"]
+	if !ok || len(organizationID) > 100 {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	organization, err := oc.Storage.GetOrganization(r.Context(), organizationID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataGet
+	resp.Result = organization
+
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
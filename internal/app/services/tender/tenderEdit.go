@@ -1,10 +1,11 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -30,20 +31,12 @@ func (tc *TenderController) TenderEdit(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderId, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	if r.Method != http.MethodPatch {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only PATCH requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPatch))
 		return
 	}
 	decoder := schema.NewDecoder()
@@ -52,14 +45,18 @@ func (tc *TenderController) TenderEdit(w http.ResponseWriter, r *http.Request) {
 	var params RequestParamsEdit
 	err := decoder.Decode(&params, r.URL.Query())
 	params.TenderID = tenderId
+	params.Username = middleware.AuthUsername(r, params.Username)
 	errValidate := validate.Struct(params)
 
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
 
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	// If-Match с версией сущности защищает от перезаписи чужих изменений
+	expectedVersion, err := etag.Parse(r.Header.Get("If-Match"))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -69,56 +66,29 @@ func (tc *TenderController) TenderEdit(w http.ResponseWriter, r *http.Request) {
 	errValidate = validate.Struct(req)
 
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request body are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidBody)
 		return
 	}
 
 	// взаимодействие с бд
 	// изменение параметров существующего тендера.
 	// пользователь не существует или некорректен. - 401
-	tender, err := tc.Storage.EditTender(r.Context(), params.TenderID, params.Username, req.TenderName, req.TenderDescription, req.TenderServiceType, "")
+	tender, err := tc.Storage.EditTender(r.Context(), params.TenderID, params.Username, req.TenderName, req.TenderDescription, req.TenderServiceType, "", expectedVersion)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	var resp ResponseDataEdit
 	resp.Result = tender
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(tender.Version))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
@@ -1,8 +1,10 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	ID "avito.go/pkg/uuid"
 	"encoding/json"
 	"errors"
@@ -22,15 +24,14 @@ type RequestDataCreate struct {
 	//Status          string `json:"status" validate:"required,oneof=Created Published Closed"`               // Статус тендера, одно из: Created, Published, Closed
 	OrganizationID  string `json:"organizationId" validate:"required,max=100"` // Уникальный идентификатор организации, максимум 100 символов
 	CreatorUsername string `json:"creatorUsername" validate:"required"`        // Уникальный slug пользователя
+
+	SubmissionDeadline *time.Time `json:"submissionDeadline"` // Необязательный срок подачи предложений в формате RFC3339
+	DecisionDeadline   *time.Time `json:"decisionDeadline"`   // Необязательный срок решения, не раньше срока подачи
 }
 
 func (tc *TenderController) CreateTender(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only POST requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPost))
 		return
 	}
 
@@ -38,13 +39,11 @@ func (tc *TenderController) CreateTender(w http.ResponseWriter, r *http.Request)
 	validate := validator.New()
 
 	err := json.NewDecoder(r.Body).Decode(&req)
+	req.CreatorUsername = middleware.AuthUsername(r, req.CreatorUsername)
 	errValidate := validate.Struct(req)
-	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	errDeadline := checkDeadlines(req.SubmissionDeadline, req.DecisionDeadline, time.Now())
+	if err != nil || errValidate != nil || errDeadline != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 	defer r.Body.Close()
@@ -60,47 +59,46 @@ func (tc *TenderController) CreateTender(w http.ResponseWriter, r *http.Request)
 		Version:        1,
 		CreatedAt:      time.Now(),
 	}
+	if req.SubmissionDeadline != nil {
+		deadline := req.SubmissionDeadline.UTC()
+		tender.SubmissionDeadline = &deadline
+	}
+	if req.DecisionDeadline != nil {
+		deadline := req.DecisionDeadline.UTC()
+		tender.DecisionDeadline = &deadline
+	}
 
 	// Создаем новый тендер
-	err = tc.Storage.Add(r.Context(), tender, req.CreatorUsername, serviceKey)
+	err = tc.Storage.AddTender(r.Context(), tender, req.CreatorUsername)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	resp.Result = tender
 
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(tender.Version))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
+
+// checkDeadlines проверяет, что сроки тендера ещё не прошли и решение принимается не раньше окончания приёма предложений
+func checkDeadlines(submission, decision *time.Time, now time.Time) error {
+	if submission != nil && !submission.After(now) {
+		return errors.New("submission deadline is in the past")
+	}
+	if decision != nil && !decision.After(now) {
+		return errors.New("decision deadline is in the past")
+	}
+	if submission != nil && decision != nil && decision.Before(*submission) {
+		return errors.New("decision deadline is before submission deadline")
+	}
+	return nil
+}
@@ -1,11 +1,11 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -27,29 +27,17 @@ func (tc *TenderController) RollbackTender(w http.ResponseWriter, r *http.Reques
 	vars := mux.Vars(r)
 	tenderID, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 	version, ok := vars["version"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	if r.Method != http.MethodPut {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only PUT requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodPut))
 		return
 	}
 
@@ -59,14 +47,11 @@ func (tc *TenderController) RollbackTender(w http.ResponseWriter, r *http.Reques
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	req.Version, _ = strconv.Atoi(version)
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -75,56 +60,22 @@ func (tc *TenderController) RollbackTender(w http.ResponseWriter, r *http.Reques
 	// пользователь не существует или некорректен - 401
 	// недостаточно прав для выполнения действия - 403
 	// тендер или версия не найдены - 404
-	tenderInterface, err := tc.Storage.RollbackVersion(r.Context(), req.TenderID, version, req.Username, serviceKey)
+	tender, err := tc.Storage.RollbackTender(r.Context(), req.TenderID, req.Version, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoVersion):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The version does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-	tender, ok := tenderInterface.(models.Tender)
-	if !ok {
-		fmt.Println("Failed to convert interface to models.Tender")
+		problem.Write(w, r, err)
+		return
 	}
 
 	var resp ResponseDataRollback
 	resp.Result = tender
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(tender.Version))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
@@ -1,11 +1,11 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/etag"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -31,11 +31,7 @@ func (tc *TenderController) TenderStatus(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	tenderID, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -45,47 +41,20 @@ func (tc *TenderController) TenderStatus(w http.ResponseWriter, r *http.Request)
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	// получаем актуальное значение статуса тендера
 	// если пользователь не существует или некорректен - 401
 
-	status, err := tc.Storage.GetStatus(r.Context(), req.TenderID, req.Username, serviceKey)
+	status, err := tc.Storage.GetTenderStatus(r.Context(), req.TenderID, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoBid):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -97,11 +66,7 @@ func (tc *TenderController) TenderUpdateStatus(w http.ResponseWriter, r *http.Re
 	vars := mux.Vars(r)
 	tenderID, ok := vars["tenderId"]
 	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -111,13 +76,10 @@ func (tc *TenderController) TenderUpdateStatus(w http.ResponseWriter, r *http.Re
 
 	err := decoder.Decode(&req, r.URL.Query())
 	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
@@ -126,38 +88,9 @@ func (tc *TenderController) TenderUpdateStatus(w http.ResponseWriter, r *http.Re
 	// если недостаточно прав для выполнения действия - 403
 	// если тендер не найден - 404
 
-	tenderInterface, err := tc.Storage.UpdateStatus(r.Context(), req.TenderID, req.Status, req.Username, serviceKey)
+	tender, err := tc.Storage.UpdateTenderStatus(r.Context(), req.TenderID, req.Status, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-	tender, ok := tenderInterface.(models.Tender)
-	if !ok {
-		fmt.Println("Failed to convert interface to []models.Tender")
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -165,11 +98,12 @@ func (tc *TenderController) TenderUpdateStatus(w http.ResponseWriter, r *http.Re
 	resp.Result = tender
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Format(tender.Version))
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
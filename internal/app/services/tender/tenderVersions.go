@@ -0,0 +1,117 @@
+package tender
+
+import (
+	"avito.go/internal/middleware"
+	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+)
+
+type ResponseDataVersions struct {
+	Result []models.Tender
+}
+
+type RequestDataVersions struct {
+	TenderID string `schema:"tenderId" validate:"required,max=100"`
+	Username string `schema:"username" validate:"required"`
+	Limit    int    `schema:"limit" validate:"gte=1,lte=100"`
+	Offset   int    `schema:"offset" validate:"gte=0"`
+}
+
+type ResponseDataDiff struct {
+	Result models.VersionDiff
+}
+
+type RequestDataDiff struct {
+	TenderID string `schema:"tenderId" validate:"required,max=100"`
+	Username string `schema:"username" validate:"required"`
+	From     int    `schema:"from" validate:"required,gte=1"`
+	To       int    `schema:"to" validate:"required,gte=1"`
+}
+
+// TenderVersions отдаёт снимки тендера от новой версии к старой, чтобы было из чего выбрать версию для отката
+func (tc *TenderController) TenderVersions(w http.ResponseWriter, r *http.Request) {
+	tenderID, ok := mux.Vars(r)["tenderId"]
+	if !ok || r.Method != http.MethodGet {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	req := RequestDataVersions{
+		Limit:  5,
+		Offset: 0,
+	}
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	tenders, err := tc.Storage.GetTenderVersions(r.Context(), req.TenderID, req.Username, req.Limit, req.Offset)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataVersions
+	resp.Result = tenders
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+// TenderDiff показывает, какие поля тендера отличаются между версиями from и to
+func (tc *TenderController) TenderDiff(w http.ResponseWriter, r *http.Request) {
+	tenderID, ok := mux.Vars(r)["tenderId"]
+	if !ok || r.Method != http.MethodGet {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	var req RequestDataDiff
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	validate := validator.New()
+
+	err := decoder.Decode(&req, r.URL.Query())
+	req.TenderID = tenderID
+	req.Username = middleware.AuthUsername(r, req.Username)
+	errValidate := validate.Struct(req)
+	if err != nil || errValidate != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+
+	diff, err := tc.Storage.DiffTenderVersions(r.Context(), req.TenderID, req.Username, req.From, req.To)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var resp ResponseDataDiff
+	resp.Result = diff
+	result, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
@@ -3,14 +3,17 @@ package tender_test
 import (
 	"avito.go/internal/app/services/tender"
 	"avito.go/internal/models"
+	"avito.go/internal/problem"
+	"avito.go/internal/storage"
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"github.com/gorilla/mux"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,7 +24,7 @@ type MockStorage struct {
 	mock.Mock
 }
 
-func (m *MockStorage) RollbackVersion(ctx context.Context, Id, version, username string, key int) (interface{}, error) {
+func (m *MockStorage) RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error) {
 	tenderD := models.Tender{
 		ID:   "1",
 		Name: "Tender 1 version 2",
@@ -29,34 +32,39 @@ func (m *MockStorage) RollbackVersion(ctx context.Context, Id, version, username
 	return tenderD, nil
 }
 
-func (m *MockStorage) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string) (models.Tender, error) {
-	editedTender := models.Tender{
-		ID:          "1",
-		Name:        "Updated Tender",
-		Description: "Updated Description",
-		ServiceType: "Delivery",
-	}
-	return editedTender, nil
+func (m *MockStorage) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error) {
+	args := m.Called(ctx, tenderId, username, tenderName, description, serviceType, status, expectedVersion)
+	return args.Get(0).(models.Tender), args.Error(1)
 }
 
-func (m *MockStorage) Add(ctx context.Context, entity interface{}, username string, key int) error {
-	args := m.Called(ctx, entity, username, key)
+func (m *MockStorage) GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error) {
+	args := m.Called(ctx, tenderID, username, limit, offset)
+	return args.Get(0).([]models.Tender), args.Error(1)
+}
+
+func (m *MockStorage) DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error) {
+	args := m.Called(ctx, tenderID, username, from, to)
+	return args.Get(0).(models.VersionDiff), args.Error(1)
+}
+
+func (m *MockStorage) AddTender(ctx context.Context, tender models.Tender, username string) error {
+	args := m.Called(ctx, tender, username)
 	return args.Error(0)
 }
 
-func (m *MockStorage) GetMy(ctx context.Context, limit int, offset int, username string, key int) (interface{}, error) {
-	args := m.Called(ctx, limit, offset, username, key)
-	return args.Get(0), args.Error(1)
+func (m *MockStorage) GetMyTenders(ctx context.Context, page models.Page, username string) ([]models.Tender, error) {
+	args := m.Called(ctx, page, username)
+	return args.Get(0).([]models.Tender), args.Error(1)
 }
 
-func (m *MockStorage) GetStatus(ctx context.Context, Id string, username string, key int) (string, error) {
-	args := m.Called(ctx, Id, username, key)
+func (m *MockStorage) GetTenderStatus(ctx context.Context, tenderID string, username string) (string, error) {
+	args := m.Called(ctx, tenderID, username)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockStorage) UpdateStatus(ctx context.Context, Id string, status string, username string, key int) (interface{}, error) {
-	args := m.Called(ctx, Id, status, username, key)
-	return args.Get(0), args.Error(1)
+func (m *MockStorage) UpdateTenderStatus(ctx context.Context, tenderID string, status string, username string) (models.Tender, error) {
+	args := m.Called(ctx, tenderID, status, username)
+	return args.Get(0).(models.Tender), args.Error(1)
 }
 
 func (m *MockStorage) GetTenderBids(ctx context.Context, tenderId string, limit int, offset int, serviceType []string) ([]models.Bid, error) {
@@ -89,8 +97,13 @@ func (m *MockStorage) GetFeedback(ctx context.Context, tenderId string, authorUs
 	return args.Get(0).([]models.FeedBack), args.Error(1)
 }
 
-func (m *MockStorage) GetTenders(ctx context.Context, limit int, offset int, serviceType []string) ([]models.Tender, error) {
-	args := m.Called(ctx, limit, offset, serviceType)
+func (m *MockStorage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStorage) GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]models.Tender), args.Error(1)
 }
 
@@ -106,7 +119,7 @@ func TestTendersInfo_Success(t *testing.T) {
 		{ID: "2", Name: "Tender B", ServiceType: "Delivery"},
 	}
 
-	mockStorage.On("GetTenders", mock.Anything, 5, 0, []string{"Construction"}).Return(expectedTenders, nil)
+	mockStorage.On("GetTenders", mock.Anything, models.TenderFilter{Limit: 5, Offset: 0, ServiceType: []string{"Construction"}}).Return(expectedTenders, nil)
 
 	tc.TendersInfo(rr, req)
 
@@ -134,7 +147,7 @@ func TestCreateTender_Success(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	mockStorage.On("Add", mock.Anything, mock.AnythingOfType("models.Tender"), "user1", mock.Anything).Return(nil)
+	mockStorage.On("AddTender", mock.Anything, mock.AnythingOfType("models.Tender"), "user1").Return(nil)
 
 	tc.CreateTender(rr, req)
 
@@ -158,7 +171,7 @@ func TestTendersMy_Success(t *testing.T) {
 		{ID: "1", Name: "Tender1", Description: "Description1", ServiceType: "Construction", Status: "Created"},
 	}
 
-	mockStorage.On("GetMy", mock.Anything, 5, 0, "user1", mock.Anything).Return(tenders, nil)
+	mockStorage.On("GetMyTenders", mock.Anything, models.Page{Limit: 5}, "user1").Return(tenders, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tenders/my?username=user1", nil)
 	rr := httptest.NewRecorder()
@@ -192,11 +205,14 @@ func TestTenderEdit_Success(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tenderD := models.Tender{
-		ID: "1", Name: "Tender1", Description: "Description1", ServiceType: "Construction", Status: "Created",
+	editedTender := models.Tender{
+		ID:          "1",
+		Name:        "Updated Tender",
+		Description: "Updated Description",
+		ServiceType: "Delivery",
 	}
-
-	editedTender, _ := mockStorage.EditTender(context.Background(), tenderD.ID, "1", tenderD.Name, tenderD.Description, tenderD.ServiceType, "status")
+	// Без If-Match версия не проверяется: в хранилище передаётся 0
+	mockStorage.On("EditTender", mock.Anything, "123", "user1", body.TenderName, body.TenderDescription, body.TenderServiceType, "", 0).Return(editedTender, nil)
 
 	req, err := http.NewRequest("PATCH", "/api/tenders/123", bytes.NewReader(bodyBytes))
 	if err != nil {
@@ -232,6 +248,40 @@ func TestTenderEdit_Success(t *testing.T) {
 		response.Result.ServiceType != editedTender.ServiceType {
 		t.Errorf("Response does not match expected tender: got %+v, want %+v", response.Result, editedTender)
 	}
+	mockStorage.AssertExpectations(t)
+}
+
+func TestTenderEdit_IfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		code    string
+	}{
+		{"weak tag", `W/"2"`, http.StatusPreconditionFailed, "version_mismatch"},
+		{"malformed tag", `2`, http.StatusBadRequest, "invalid_if_match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &MockStorage{}
+			tc := &tender.TenderController{Storage: mockStorage}
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/tenders/1/edit?username=user1", strings.NewReader(`{"name":"Updated Tender"}`))
+			req = mux.SetURLVars(req, map[string]string{"tenderId": "1"})
+			req.Header.Set("If-Match", tt.ifMatch)
+			rr := httptest.NewRecorder()
+
+			tc.TenderEdit(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			var response problem.Problem
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			// До хранилища запрос не доходит
+			mockStorage.AssertNotCalled(t, "EditTender")
+		})
+	}
 }
 
 func TestRollbackTender_Success(t *testing.T) {
@@ -240,12 +290,7 @@ func TestRollbackTender_Success(t *testing.T) {
 		Storage: mockStorage,
 	}
 
-	RollbackTenderInterface, _ := mockStorage.RollbackVersion(context.Background(), "1", "creatred", "username", 2)
-
-	RollbackTender, ok := RollbackTenderInterface.(models.Tender)
-	if !ok {
-		fmt.Println("Failed to convert interface to models.Tender")
-	}
+	RollbackTender, _ := mockStorage.RollbackTender(context.Background(), "1", 2, "username")
 
 	req, err := http.NewRequest("PUT", "/api/tenders/1/rollback/2?username=user1", nil)
 	if err != nil {
@@ -273,6 +318,235 @@ func TestRollbackTender_Success(t *testing.T) {
 	}
 
 	if response.Result.ID != RollbackTender.ID || response.Result.Name != RollbackTender.Name {
-		t.Errorf("Expected tender %+v, got %+v", RollbackTenderInterface, response.Result)
+		t.Errorf("Expected tender %+v, got %+v", RollbackTender, response.Result)
+	}
+}
+
+func TestTenderVersions_Success(t *testing.T) {
+	mockStorage := &MockStorage{}
+	tc := &tender.TenderController{
+		Storage: mockStorage,
+	}
+
+	versions := []models.Tender{
+		{ID: "1", Name: "Tender 1 version 2", Version: 2},
+		{ID: "1", Name: "Tender 1", Version: 1},
+	}
+	mockStorage.On("GetTenderVersions", mock.Anything, "1", "user1", 5, 0).Return(versions, nil)
+
+	req, err := http.NewRequest("GET", "/api/tenders/1/versions?username=user1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "1"})
+
+	rr := httptest.NewRecorder()
+
+	tc.TenderVersions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response tender.ResponseDataVersions
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, versions, response.Result)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestTenderDiff_Success(t *testing.T) {
+	mockStorage := &MockStorage{}
+	tc := &tender.TenderController{
+		Storage: mockStorage,
+	}
+
+	diff := models.DiffTenders(
+		models.Tender{Name: "Tender 1", Description: "Same", Version: 1},
+		models.Tender{Name: "Tender 1 version 2", Description: "Same", Version: 2},
+	)
+	mockStorage.On("DiffTenderVersions", mock.Anything, "1", "user1", 1, 2).Return(diff, nil)
+
+	req, err := http.NewRequest("GET", "/api/tenders/1/diff?username=user1&from=1&to=2", nil)
+	if err != nil {
+		t.Fatal(err)
 	}
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "1"})
+
+	rr := httptest.NewRecorder()
+
+	tc.TenderDiff(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response tender.ResponseDataDiff
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(response.Result.Changes))
+	assert.Equal(t, "name", response.Result.Changes[0].Field)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestTenderDiff_MissingVersion(t *testing.T) {
+	mockStorage := &MockStorage{}
+	tc := &tender.TenderController{
+		Storage: mockStorage,
+	}
+
+	req, err := http.NewRequest("GET", "/api/tenders/1/diff?username=user1&from=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "1"})
+
+	rr := httptest.NewRecorder()
+
+	tc.TenderDiff(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockStorage.AssertNotCalled(t, "DiffTenderVersions")
+}
+
+func TestTendersInfo_Search(t *testing.T) {
+	mockStorage := new(MockStorage)
+	tc := tender.TenderController{Storage: mockStorage}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders?q=+road+repair+", nil)
+	rr := httptest.NewRecorder()
+
+	expectedTenders := []models.Tender{
+		{ID: "1", Name: "Road repair", Rank: 0.6, Snippet: "<b>Road</b> <b>repair</b> in the city"},
+	}
+	mockStorage.On("GetTenders", mock.Anything, models.TenderFilter{Limit: 5, Offset: 0, Query: "road repair"}).Return(expectedTenders, nil)
+
+	tc.TendersInfo(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response tender.ResponseDataInfo
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTenders, response.Result)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestTendersInfo_FilterAndSort(t *testing.T) {
+	mockStorage := new(MockStorage)
+	tc := tender.TenderController{Storage: mockStorage}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders?status=Published&status=Closed&organization_id=o1"+
+		"&created_from=2024-09-01T00:00:00Z&created_to=2024-09-30T00:00:00Z&sort=createdAt&order=desc", nil)
+	rr := httptest.NewRecorder()
+
+	mockStorage.On("GetTenders", mock.Anything, models.TenderFilter{
+		Limit:          5,
+		Status:         []string{"Published", "Closed"},
+		OrganizationID: "o1",
+		CreatedFrom:    time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		CreatedTo:      time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+		Sort:           models.TenderSortCreatedAt,
+		Desc:           true,
+	}).Return([]models.Tender{}, nil)
+
+	tc.TendersInfo(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestTendersInfo_InvalidFilter(t *testing.T) {
+	tc := tender.TenderController{Storage: new(MockStorage)}
+
+	for _, query := range []string{
+		"status=Draft",
+		"sort=price",
+		"order=up",
+		"created_from=yesterday",
+		"created_from=2024-09-30T00:00:00Z&created_to=2024-09-01T00:00:00Z",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenders?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		tc.TendersInfo(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestTendersInfo_Cursor(t *testing.T) {
+	mockStorage := new(MockStorage)
+	tc := tender.TenderController{Storage: mockStorage}
+
+	first := []models.Tender{{ID: "1", Name: "Alpha"}, {ID: "2", Name: "Beta"}}
+	mockStorage.On("GetTenders", mock.Anything, models.TenderFilter{Limit: 2}).Return(first, nil)
+	mockStorage.On("GetTenders", mock.Anything, models.TenderFilter{Limit: 2, After: []string{"Beta", "2"}}).Return([]models.Tender{{ID: "3", Name: "Gamma"}}, nil)
+
+	rr := httptest.NewRecorder()
+	tc.TendersInfo(rr, httptest.NewRequest(http.MethodGet, "/api/tenders?limit=2", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response tender.ResponseDataInfo
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.NextCursor)
+	next := response.NextCursor
+
+	rr = httptest.NewRecorder()
+	tc.TendersInfo(rr, httptest.NewRequest(http.MethodGet, "/api/tenders?limit=2&cursor="+next, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	response = tender.ResponseDataInfo{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Result, 1)
+	assert.Empty(t, response.NextCursor)
+
+	// Курсор, выданный для другой сортировки, не принимается
+	rr = httptest.NewRecorder()
+	tc.TendersInfo(rr, httptest.NewRequest(http.MethodGet, "/api/tenders?limit=2&sort=createdAt&cursor="+next, nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestCreateTender_Deadlines(t *testing.T) {
+	mockStorage := new(MockStorage)
+	tc := tender.TenderController{Storage: mockStorage}
+
+	submission := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	decision := submission.Add(24 * time.Hour)
+	body := func(submission, decision time.Time) string {
+		return `{"name": "tender1", "description": "description", "serviceType": "Construction", "organizationId": "1",
+			"creatorUsername": "user1", "submissionDeadline": "` + submission.Format(time.RFC3339) + `", "decisionDeadline": "` + decision.Format(time.RFC3339) + `"}`
+	}
+
+	mockStorage.On("AddTender", mock.Anything, mock.MatchedBy(func(t models.Tender) bool {
+		return t.SubmissionDeadline.Equal(submission) && t.DecisionDeadline.Equal(decision) && t.SubmissionDeadline.Location() == time.UTC
+	}), "user1").Return(nil)
+
+	rr := httptest.NewRecorder()
+	tc.CreateTender(rr, httptest.NewRequest(http.MethodPost, "/api/tenders/new", bytes.NewBufferString(body(submission, decision))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockStorage.AssertExpectations(t)
+
+	// Прошедший срок и решение раньше окончания приёма предложений
+	for _, deadlines := range [][2]time.Time{
+		{time.Now().Add(-time.Hour), decision},
+		{decision, submission},
+	} {
+		rr = httptest.NewRecorder()
+		tc.CreateTender(rr, httptest.NewRequest(http.MethodPost, "/api/tenders/new", bytes.NewBufferString(body(deadlines[0], deadlines[1]))))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestTenderUpdateStatus_InvalidTransition(t *testing.T) {
+	mockStorage := new(MockStorage)
+	tc := tender.TenderController{Storage: mockStorage}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/1/status?status=Created&username=user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"tenderId": "1"})
+	rr := httptest.NewRecorder()
+
+	mockStorage.On("UpdateTenderStatus", mock.Anything, "1", "Created", "user1").Return(models.Tender{}, storage.ErrInvalidTransition)
+
+	tc.TenderUpdateStatus(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockStorage.AssertExpectations(t)
 }
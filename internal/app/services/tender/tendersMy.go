@@ -1,33 +1,31 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/cursor"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
 	"net/http"
 )
 
 type ResponseDataMy struct {
-	Result []models.Tender
+	Result     []models.Tender
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы; пуст на последней
 }
 
 type RequestDataMy struct {
 	Limit    int    `schema:"limit" validate:"gte=1,lte=100"`
 	Offset   int    `schema:"offset" validate:"gte=0"`
+	Cursor   string `schema:"cursor"` // Курсор next_cursor предыдущей страницы; если задан, offset не учитывается
 	Username string `schema:"username"`
 }
 
 func (tc *TenderController) TendersMy(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only GET requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
 		return
 	}
 
@@ -39,58 +37,30 @@ func (tc *TenderController) TendersMy(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New()
 
 	err := decoder.Decode(&req, r.URL.Query())
+	req.Username = middleware.AuthUsername(r, req.Username)
 	errValidate := validate.Struct(req)
-	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+	after, errCursor := cursor.Decode(req.Cursor, models.OrderByName)
+	if err != nil || errValidate != nil || errCursor != nil {
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
 
 	// взоимодействие с бд. Получаем список всех тендеров юзера
-	tendersInterface, err := tc.Storage.GetMy(r.Context(), req.Limit, req.Offset, req.Username, serviceKey)
+	tenders, err := tc.Storage.GetMyTenders(r.Context(), models.Page{Limit: req.Limit, Offset: req.Offset, After: after}, req.Username)
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-	tenders, ok := tendersInterface.([]models.Tender)
-	if !ok {
-		fmt.Println("Failed to convert interface to []models.Tender")
+		problem.Write(w, r, err)
 		return
 	}
 
-	var resp ResponseDataInfo
+	var resp ResponseDataMy
 	resp.Result = tenders
+	if len(tenders) == req.Limit {
+		resp.NextCursor = cursor.Encode(models.OrderByName, models.TenderKey(tenders[len(tenders)-1])...)
+	}
 
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
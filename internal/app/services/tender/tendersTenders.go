@@ -1,32 +1,45 @@
 package tender
 
 import (
+	"avito.go/internal/middleware"
 	"avito.go/internal/models"
-	"avito.go/internal/storage"
+	"avito.go/internal/problem"
+	"avito.go/pkg/cursor"
 	"encoding/json"
-	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
 	"net/http"
+	"reflect"
+	"strings"
+	"time"
 )
 
 type ResponseDataInfo struct {
-	Result []models.Tender
+	Result     []models.Tender
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы; пуст на последней
 }
 
 type RequestDataInfo struct {
 	Limit       int      `schema:"limit" validate:"gte=1,lte=100"`                                                 // Параметр limit (min 1, max 100)
 	Offset      int      `schema:"offset" validate:"gte=0"`                                                        // Параметр offset (минимум 0)
 	ServiceType []string `schema:"service_type" validate:"omitempty,dive,oneof=Construction Delivery Manufacture"` // Параметр service_type с возможными значениями
+	Query       string   `schema:"q" validate:"max=200"`                                                           // Поисковый запрос по названию и описанию
+	Cursor      string   `schema:"cursor"`                                                                         // Курсор next_cursor предыдущей страницы; если задан, offset не учитывается
+
+	Status         []string  `schema:"status" validate:"omitempty,dive,oneof=Created Published Closed"` // Черновики и закрытые тендеры видны только ответственным
+	OrganizationID string    `schema:"organization_id" validate:"max=100"`
+	CreatedFrom    time.Time `schema:"created_from"` // Границы периодов включительно, в формате RFC3339
+	CreatedTo      time.Time `schema:"created_to" validate:"omitempty,gtefield=CreatedFrom"`
+	UpdatedFrom    time.Time `schema:"updated_from"`
+	UpdatedTo      time.Time `schema:"updated_to" validate:"omitempty,gtefield=UpdatedFrom"`
+	Sort           string    `schema:"sort" validate:"omitempty,oneof=name createdAt updatedAt"`
+	Order          string    `schema:"order" validate:"omitempty,oneof=asc desc"`
+	Username       string    `schema:"username"` // Читается middleware.Optional в legacy-режиме
 }
 
 func (tc *TenderController) TendersInfo(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "Only GET requests are supported"}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.MethodNotSupported(http.MethodGet))
 		return
 	}
 
@@ -36,58 +49,55 @@ func (tc *TenderController) TendersInfo(w http.ResponseWriter, r *http.Request)
 	}
 
 	decoder := schema.NewDecoder()
+	decoder.RegisterConverter(time.Time{}, convertTime)
 	validate := validator.New()
 
 	err := decoder.Decode(&req, r.URL.Query())
 	errValidate := validate.Struct(req)
 	if err != nil || errValidate != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-
-		response := ErrorResponse{Reason: "The request parameters are incorrect."}
-		json.NewEncoder(w).Encode(response)
+		problem.Write(w, r, problem.ErrInvalidParameters)
 		return
 	}
-	// Работа с бд, Список тендеров с возможностью фильтрации по типу услуг.
-	//Если фильтры не заданы, возвращаются все тендеры.
-	tenders, err := tc.Storage.GetTenders(r.Context(), req.Limit, req.Offset, req.ServiceType)
+	// Работа с бд, Список тендеров с фильтрами, сортировкой и поиском по словам.
+	//Если фильтры не заданы, возвращаются все опубликованные тендеры.
+	filter := models.TenderFilter{
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+		ServiceType:    req.ServiceType,
+		Query:          strings.TrimSpace(req.Query),
+		Status:         req.Status,
+		OrganizationID: req.OrganizationID,
+		CreatedFrom:    req.CreatedFrom,
+		CreatedTo:      req.CreatedTo,
+		UpdatedFrom:    req.UpdatedFrom,
+		UpdatedTo:      req.UpdatedTo,
+		Sort:           req.Sort,
+		Desc:           req.Order == "desc",
+	}
+	if user, ok := middleware.UserFromContext(r.Context()); ok {
+		filter.Username = user.Username
+	}
+	filter.After, err = cursor.Decode(req.Cursor, filter.Order())
 	if err != nil {
-		switch {
-		case errors.Is(err, storage.ErrRights):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-
-			response := ErrorResponse{Reason: "Insufficient rights to perform the action."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoTender):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-
-			response := ErrorResponse{Reason: "The tender does not exist."}
-			json.NewEncoder(w).Encode(response)
-			return
-		case errors.Is(err, storage.ErrNoUser):
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-
-			response := ErrorResponse{Reason: "The user does not exist or is invalid."}
-			json.NewEncoder(w).Encode(response)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+		problem.Write(w, r, problem.ErrInvalidParameters)
+		return
+	}
+	tenders, err := tc.Storage.GetTenders(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
 	}
-	//TODO: отсортировать по алфавиту по названию
 	//TODO: добавить логику обработки данных, учесть лимит и сдвиг
 
 	var resp ResponseDataInfo
 	resp.Result = tenders
+	if len(tenders) == req.Limit {
+		resp.NextCursor = cursor.Encode(filter.Order(), filter.Key(tenders[len(tenders)-1])...)
+	}
 
 	result, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -95,3 +105,12 @@ func (tc *TenderController) TendersInfo(w http.ResponseWriter, r *http.Request)
 	w.Write(result)
 	w.WriteHeader(http.StatusOK)
 }
+
+// convertTime разбирает даты фильтра в RFC3339; пустое reflect.Value schema превращает в ошибку конвертации
+func convertTime(value string) reflect.Value {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(t)
+}
@@ -0,0 +1,20 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store хранит содержимое файлов по ключу. Сведения о файлах (имя, размер, контрольная сумма) лежат в storage,
+// поэтому реализацию можно заменить, например, на S3, не трогая обработчики.
+type Store interface {
+	// Put сохраняет содержимое целиком: при ошибке чтения r по ключу ничего не остаётся
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет содержимое; отсутствие ключа ошибкой не считается
+	Delete(ctx context.Context, key string) error
+}
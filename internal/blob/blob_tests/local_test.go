@@ -0,0 +1,67 @@
+package blob_test
+
+import (
+	"avito.go/internal/blob"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocal(filepath.Join(t.TempDir(), "attachments"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "a1", strings.NewReader("hello")))
+
+	content, err := store.Get(ctx, "a1")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	content.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "a1"))
+	_, err = store.Get(ctx, "a1")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "a1"))
+}
+
+func TestLocal_FailedPutLeavesNothing(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := blob.NewLocal(dir)
+	require.NoError(t, err)
+
+	broken := io.MultiReader(strings.NewReader("partial"), &failingReader{})
+	assert.Error(t, store.Put(ctx, "a1", broken))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocal_RejectsKeysOutsideDir(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", ".", "..", "../a1", "dir/a1", `dir\a1`, ".upload-1"} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x")), blob.ErrInvalidKey, key)
+		_, err = store.Get(ctx, key)
+		assert.ErrorIs(t, err, blob.ErrInvalidKey, key)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"github.com/caarlos0/env/v8"
+	"slices"
+	"strings"
+	"time"
 )
 
+// minAuthSecretLength совпадает с middleware.MinSecretLength: config не может импортировать middleware из-за цикла импортов
+const minAuthSecretLength = 32
+
 type Config struct {
 	ServerAddress   string `env:"SERVER_ADDRESS" envDefault:":8080"`
 	PostgresConn    string `env:"POSTGRES_CONN"`
@@ -13,12 +21,110 @@ type Config struct {
 	PostgresHost    string `env:"POSTGRES_HOST"`
 	PostgresPort    string `env:"POSTGRES_PORT" envDefault:"5432"`
 	PostgresDB      string `env:"POSTGRES_DATABASE"`
+
+	StorageType string `env:"STORAGE_TYPE" envDefault:"postgres"` // postgres или memory
+	StorageSeed string `env:"STORAGE_SEED"`                       // JSON с начальными данными для хранилища memory
+
+	MigrateRetryInterval time.Duration `env:"MIGRATE_RETRY_INTERVAL" envDefault:"5s"` // Пауза между попытками применить миграции, пока база недоступна
+	StartupTimeout       time.Duration `env:"STARTUP_TIMEOUT" envDefault:"30s"`       // Сколько ждать доступности базы и применения миграций при старте, прежде чем завершиться с ошибкой
+	ShutdownTimeout      time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"15s"`      // Сколько ждать завершения обрабатываемых запросов при остановке
+
+	AttachmentDir     string `env:"ATTACHMENT_DIR" envDefault:"data/attachments"` // Каталог с содержимым приложенных файлов
+	AttachmentMaxSize int64  `env:"ATTACHMENT_MAX_SIZE" envDefault:"10485760"`    // Максимальный размер одного файла в байтах
+
+	SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1m"` // Период закрытия тендеров с истёкшими сроками; 0 отключает планировщик
+
+	AccessLogSampleRate float64 `env:"ACCESS_LOG_SAMPLE_RATE" envDefault:"1"` // Доля успешных запросов в журнале доступа; ошибки пишутся всегда
+
+	TracingExporter    string `env:"TRACING_EXPORTER" envDefault:"none"`               // none, stdout или otlp (адрес коллектора - в OTEL_EXPORTER_OTLP_ENDPOINT)
+	TracingServiceName string `env:"TRACING_SERVICE_NAME" envDefault:"tender-service"` // Имя сервиса в span'ах
+
+	AuthSecret         string        `env:"AUTH_SECRET"`                             // Ключ подписи токенов, обязателен, не короче 32 байт
+	AuthTokenTTL       time.Duration `env:"AUTH_TOKEN_TTL" envDefault:"24h"`         // Время жизни выданного токена
+	AuthLegacyUsername bool          `env:"AUTH_LEGACY_USERNAME" envDefault:"false"` // Разрешить запросы без токена с username в параметрах
 }
 
+// Load читает конфигурацию из окружения и проверяет её, чтобы сервис не стартовал с заведомо неверными настройками
 func Load() (*Config, error) {
+	return load((*Config).Validate)
+}
+
+// LoadDatabase читает конфигурацию для подкоманды migrate: проверяются только настройки подключения к базе
+func LoadDatabase() (*Config, error) {
+	return load((*Config).ValidateDatabase)
+}
+
+func load(validate func(*Config) error) (*Config, error) {
 	cfg := &Config{}
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
+
+// ValidateDatabase проверяет настройки подключения к Postgres
+func (cfg *Config) ValidateDatabase() error {
+	return errors.Join(cfg.databaseErrors()...)
+}
+
+func (cfg *Config) databaseErrors() []error {
+	var errs []error
+	if cfg.PostgresConn == "" && cfg.PostgresJDBCUrl == "" && (cfg.PostgresHost == "" || cfg.PostgresDB == "") {
+		errs = append(errs, errors.New("postgres storage needs POSTGRES_CONN, POSTGRES_JDBC_URL or POSTGRES_HOST with POSTGRES_DATABASE"))
+	}
+	if cfg.PostgresConn == "" && cfg.PostgresJDBCUrl != "" && !strings.HasPrefix(cfg.PostgresJDBCUrl, "jdbc:postgresql://") {
+		errs = append(errs, errors.New("POSTGRES_JDBC_URL must start with jdbc:postgresql://"))
+	}
+	return errs
+}
+
+// Validate возвращает все найденные ошибки конфигурации разом
+func (cfg *Config) Validate() error {
+	var errs []error
+	switch cfg.StorageType {
+	case "memory":
+	case "postgres":
+		errs = append(errs, cfg.databaseErrors()...)
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE_TYPE must be postgres or memory, got %q", cfg.StorageType))
+	}
+	if cfg.ServerAddress == "" {
+		errs = append(errs, errors.New("SERVER_ADDRESS must not be empty"))
+	}
+	if cfg.MigrateRetryInterval <= 0 {
+		errs = append(errs, errors.New("MIGRATE_RETRY_INTERVAL must be positive"))
+	}
+	if cfg.StartupTimeout <= 0 {
+		errs = append(errs, errors.New("STARTUP_TIMEOUT must be positive"))
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if cfg.AttachmentDir == "" {
+		errs = append(errs, errors.New("ATTACHMENT_DIR must not be empty"))
+	}
+	if cfg.AttachmentMaxSize <= 0 {
+		errs = append(errs, errors.New("ATTACHMENT_MAX_SIZE must be positive"))
+	}
+	if cfg.SchedulerInterval < 0 {
+		errs = append(errs, errors.New("SCHEDULER_INTERVAL must not be negative"))
+	}
+	if cfg.AccessLogSampleRate < 0 || cfg.AccessLogSampleRate > 1 {
+		errs = append(errs, errors.New("ACCESS_LOG_SAMPLE_RATE must be between 0 and 1"))
+	}
+	if !slices.Contains([]string{"none", "stdout", "otlp"}, cfg.TracingExporter) {
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER must be none, stdout or otlp, got %q", cfg.TracingExporter))
+	}
+	if cfg.AuthSecret == "" {
+		errs = append(errs, errors.New("AUTH_SECRET must be set"))
+	} else if len(cfg.AuthSecret) < minAuthSecretLength {
+		errs = append(errs, fmt.Errorf("AUTH_SECRET must be at least %d bytes", minAuthSecretLength))
+	}
+	if cfg.AuthTokenTTL <= 0 {
+		errs = append(errs, errors.New("AUTH_TOKEN_TTL must be positive"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+// Registry - реестр метрик сервиса; отдаётся обработчиком Handler на /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Количество HTTP-запросов по шаблону маршрута, методу и коду ответа.",
+	}, []string{"route", "method", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Время обработки HTTP-запроса по шаблону маршрута, методу и коду ответа.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	StorageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "storage_call_duration_seconds",
+		Help:    "Время вызова метода хранилища.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	TendersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tenders_created_total",
+		Help: "Количество созданных тендеров.",
+	})
+
+	BidsSubmitted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "bids_submitted_total",
+		Help: "Количество поданных предложений.",
+	})
+
+	Decisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bid_decisions_total",
+		Help: "Количество решений по предложениям по исходу: Approved или Rejected.",
+	}, []string{"decision"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		StorageDuration,
+		TendersCreated,
+		BidsSubmitted,
+		Decisions,
+	)
+}
+
+// RegisterDB добавляет статистику пула соединений sql.DB.Stats() с меткой db_name
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"avito.go/internal/metrics"
+	"avito.go/internal/models"
+	"avito.go/internal/storage/memory"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_BusinessCounters(t *testing.T) {
+	mem := memory.New()
+	require.NoError(t, mem.Load(memory.Seed{
+		Employees: []memory.SeedEmployee{
+			{User: models.User{ID: "u1", Username: "alice"}},
+			{User: models.User{ID: "u2", Username: "dave"}},
+		},
+		Organizations: []models.Organization{{ID: "o1", Name: "Org"}, {ID: "o2", Name: "Vendor"}},
+		Responsibles: []memory.SeedResponsible{
+			{OrganizationID: "o1", Username: "alice"},
+			{OrganizationID: "o2", Username: "dave"},
+		},
+	}))
+	s := metrics.NewStorage(mem)
+	ctx := context.Background()
+
+	tenders := testutil.ToFloat64(metrics.TendersCreated)
+	bids := testutil.ToFloat64(metrics.BidsSubmitted)
+	approved := testutil.ToFloat64(metrics.Decisions.WithLabelValues("Approved"))
+
+	require.NoError(t, s.AddTender(ctx, models.Tender{ID: "t1", Name: "Tender", Status: "Published", OrganizationID: "o1", Version: 1}, "alice"))
+	// Неудачный вызов не считается
+	assert.Error(t, s.AddTender(ctx, models.Tender{ID: "t2", Name: "Foreign", OrganizationID: "o1", Version: 1}, "dave"))
+	require.NoError(t, s.AddBid(ctx, models.Bid{ID: "b1", Name: "Bid", Status: "Published", TenderID: "t1", AuthorType: "User", AuthorID: "u2", Version: 1}, "u2"))
+	_, err := s.SubmitDecisionBid(ctx, "b1", "Approved", "alice")
+	require.NoError(t, err)
+
+	assert.Equal(t, tenders+1, testutil.ToFloat64(metrics.TendersCreated))
+	assert.Equal(t, bids+1, testutil.ToFloat64(metrics.BidsSubmitted))
+	assert.Equal(t, approved+1, testutil.ToFloat64(metrics.Decisions.WithLabelValues("Approved")))
+}
+
+func TestHandler(t *testing.T) {
+	metrics.StorageDuration.WithLabelValues("GetTenders").Observe(0.01)
+
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.True(t, strings.Contains(body, `storage_call_duration_seconds_count{method="GetTenders"}`))
+	assert.True(t, strings.Contains(body, "go_goroutines"))
+}
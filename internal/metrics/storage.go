@@ -0,0 +1,231 @@
+package metrics
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"time"
+)
+
+// Storage замеряет время каждого вызова хранилища и считает бизнес-события:
+// созданные тендеры, поданные предложения и решения по ним
+type Storage struct {
+	storage.Storage
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+func NewStorage(s storage.Storage) *Storage {
+	return &Storage{Storage: s}
+}
+
+func observe(method string, start time.Time) {
+	StorageDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func (s *Storage) AddTender(ctx context.Context, tender models.Tender, username string) error {
+	defer observe("AddTender", time.Now())
+	err := s.Storage.AddTender(ctx, tender, username)
+	if err == nil {
+		TendersCreated.Inc()
+	}
+	return err
+}
+
+func (s *Storage) GetMyTenders(ctx context.Context, page models.Page, username string) ([]models.Tender, error) {
+	defer observe("GetMyTenders", time.Now())
+	return s.Storage.GetMyTenders(ctx, page, username)
+}
+
+func (s *Storage) GetTenderStatus(ctx context.Context, tenderID, username string) (string, error) {
+	defer observe("GetTenderStatus", time.Now())
+	return s.Storage.GetTenderStatus(ctx, tenderID, username)
+}
+
+func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderID, status, username string) (models.Tender, error) {
+	defer observe("UpdateTenderStatus", time.Now())
+	return s.Storage.UpdateTenderStatus(ctx, tenderID, status, username)
+}
+
+func (s *Storage) RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error) {
+	defer observe("RollbackTender", time.Now())
+	return s.Storage.RollbackTender(ctx, tenderID, version, username)
+}
+
+func (s *Storage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	defer observe("CloseExpiredTenders", time.Now())
+	return s.Storage.CloseExpiredTenders(ctx, now)
+}
+
+func (s *Storage) GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error) {
+	defer observe("GetTenders", time.Now())
+	return s.Storage.GetTenders(ctx, filter)
+}
+
+func (s *Storage) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error) {
+	defer observe("EditTender", time.Now())
+	return s.Storage.EditTender(ctx, tenderId, username, tenderName, description, serviceType, status, expectedVersion)
+}
+
+func (s *Storage) GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error) {
+	defer observe("GetTenderVersions", time.Now())
+	return s.Storage.GetTenderVersions(ctx, tenderID, username, limit, offset)
+}
+
+func (s *Storage) DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error) {
+	defer observe("DiffTenderVersions", time.Now())
+	return s.Storage.DiffTenderVersions(ctx, tenderID, username, from, to)
+}
+
+func (s *Storage) AddBid(ctx context.Context, bid models.Bid, userID string) error {
+	defer observe("AddBid", time.Now())
+	err := s.Storage.AddBid(ctx, bid, userID)
+	if err == nil {
+		BidsSubmitted.Inc()
+	}
+	return err
+}
+
+func (s *Storage) GetMyBids(ctx context.Context, page models.Page, username string) ([]models.Bid, error) {
+	defer observe("GetMyBids", time.Now())
+	return s.Storage.GetMyBids(ctx, page, username)
+}
+
+func (s *Storage) GetBidStatus(ctx context.Context, bidID, username string) (string, error) {
+	defer observe("GetBidStatus", time.Now())
+	return s.Storage.GetBidStatus(ctx, bidID, username)
+}
+
+func (s *Storage) UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error) {
+	defer observe("UpdateBidStatus", time.Now())
+	return s.Storage.UpdateBidStatus(ctx, bidID, status, username)
+}
+
+func (s *Storage) RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error) {
+	defer observe("RollbackBid", time.Now())
+	return s.Storage.RollbackBid(ctx, bidID, version, username)
+}
+
+func (s *Storage) GetTenderBids(ctx context.Context, tenderId, username string, page models.Page) ([]models.Bid, error) {
+	defer observe("GetTenderBids", time.Now())
+	return s.Storage.GetTenderBids(ctx, tenderId, username, page)
+}
+
+func (s *Storage) SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) {
+	defer observe("SubmitDecisionBid", time.Now())
+	bid, err := s.Storage.SubmitDecisionBid(ctx, bidId, decision, username)
+	if err == nil {
+		Decisions.WithLabelValues(decision).Inc()
+	}
+	return bid, err
+}
+
+func (s *Storage) EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error) {
+	defer observe("EditBid", time.Now())
+	return s.Storage.EditBid(ctx, bidId, username, bidName, description, status, expectedVersion)
+}
+
+func (s *Storage) GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error) {
+	defer observe("GetBidVersions", time.Now())
+	return s.Storage.GetBidVersions(ctx, bidID, username, limit, offset)
+}
+
+func (s *Storage) DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error) {
+	defer observe("DiffBidVersions", time.Now())
+	return s.Storage.DiffBidVersions(ctx, bidID, username, from, to)
+}
+
+func (s *Storage) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) {
+	defer observe("AddFeedbackBid", time.Now())
+	return s.Storage.AddFeedbackBid(ctx, bidId, bidFeedback, username)
+}
+
+func (s *Storage) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, page models.Page) ([]models.FeedBack, error) {
+	defer observe("GetFeedback", time.Now())
+	return s.Storage.GetFeedback(ctx, tenderId, authorUsername, requesterUsername, page)
+}
+
+func (s *Storage) GetUserCredentials(ctx context.Context, username string) (models.User, string, error) {
+	defer observe("GetUserCredentials", time.Now())
+	return s.Storage.GetUserCredentials(ctx, username)
+}
+
+func (s *Storage) CreateOrganization(ctx context.Context, organization models.Organization, username string) error {
+	defer observe("CreateOrganization", time.Now())
+	return s.Storage.CreateOrganization(ctx, organization, username)
+}
+
+func (s *Storage) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	defer observe("GetOrganizations", time.Now())
+	return s.Storage.GetOrganizations(ctx, limit, offset)
+}
+
+func (s *Storage) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	defer observe("GetOrganization", time.Now())
+	return s.Storage.GetOrganization(ctx, organizationID)
+}
+
+func (s *Storage) EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error) {
+	defer observe("EditOrganization", time.Now())
+	return s.Storage.EditOrganization(ctx, organizationID, username, name, description, organizationType)
+}
+
+func (s *Storage) DeleteOrganization(ctx context.Context, organizationID, username string) error {
+	defer observe("DeleteOrganization", time.Now())
+	return s.Storage.DeleteOrganization(ctx, organizationID, username)
+}
+
+func (s *Storage) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	defer observe("GetOrganizationResponsibles", time.Now())
+	return s.Storage.GetOrganizationResponsibles(ctx, organizationID)
+}
+
+func (s *Storage) AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	defer observe("AddOrganizationResponsible", time.Now())
+	return s.Storage.AddOrganizationResponsible(ctx, organizationID, username, employeeUsername)
+}
+
+func (s *Storage) RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	defer observe("RemoveOrganizationResponsible", time.Now())
+	return s.Storage.RemoveOrganizationResponsible(ctx, organizationID, username, employeeUsername)
+}
+
+func (s *Storage) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	defer observe("GetEmployees", time.Now())
+	return s.Storage.GetEmployees(ctx, prefix, limit, offset)
+}
+
+func (s *Storage) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	defer observe("GetEmployee", time.Now())
+	return s.Storage.GetEmployee(ctx, username)
+}
+
+func (s *Storage) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	defer observe("GetEmployeeOrganizations", time.Now())
+	return s.Storage.GetEmployeeOrganizations(ctx, username)
+}
+
+func (s *Storage) GetAuditEvents(ctx context.Context, filter models.AuditFilter, page models.Page, username string) ([]models.AuditEvent, error) {
+	defer observe("GetAuditEvents", time.Now())
+	return s.Storage.GetAuditEvents(ctx, filter, page, username)
+}
+
+func (s *Storage) CanAttach(ctx context.Context, entityType, entityID, username string) error {
+	defer observe("CanAttach", time.Now())
+	return s.Storage.CanAttach(ctx, entityType, entityID, username)
+}
+
+func (s *Storage) AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error) {
+	defer observe("AddAttachment", time.Now())
+	return s.Storage.AddAttachment(ctx, attachment, username)
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error) {
+	defer observe("GetAttachments", time.Now())
+	return s.Storage.GetAttachments(ctx, entityType, entityID, version, username)
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error) {
+	defer observe("GetAttachment", time.Now())
+	return s.Storage.GetAttachment(ctx, attachmentID, username)
+}
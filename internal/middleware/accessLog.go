@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"avito.go/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// AccessLog пишет строку журнала доступа после того, как обработчик отработал.
+// Ответы с ошибкой (4xx, 5xx) пишутся всегда, успешные - с вероятностью SampleRate.
+type AccessLog struct {
+	SampleRate float64 // Доля успешных запросов в журнале: 1 - все, 0 - ни одного
+
+	sample func() float64 // Источник случайных чисел из [0, 1), подменяется в тестах
+}
+
+func NewAccessLog(sampleRate float64) *AccessLog {
+	return &AccessLog{SampleRate: sampleRate, sample: rand.Float64}
+}
+
+// accessEntry - данные строки журнала, которые становятся известны глубже по цепочке обработчиков
+type accessEntry struct {
+	username string
+}
+
+// responseRecorder запоминает код ответа и количество записанных байт тела
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	n, err := rr.ResponseWriter.Write(b)
+	rr.bytes += n
+	return n, err
+}
+
+// statusCode - код ответа; обработчик, ничего не записавший, отвечает 200
+func (rr *responseRecorder) statusCode() int {
+	if rr.status == 0 {
+		return http.StatusOK
+	}
+	return rr.status
+}
+
+// Unwrap нужен http.ResponseController, чтобы добраться до исходного ResponseWriter
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+func (a *AccessLog) Handler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessEntry{}
+		rec := &responseRecorder{ResponseWriter: w}
+
+		h.ServeHTTP(rec, r.WithContext(withAccessEntry(r.Context(), entry)))
+
+		status := rec.statusCode()
+		if status < http.StatusBadRequest && (a.SampleRate <= 0 || a.sample() >= a.SampleRate) {
+			return
+		}
+
+		logger.FromContext(r.Context()).Info(
+			"access",
+			zap.String("method", r.Method),
+			zap.String("route", routeTemplate(r)),
+			zap.Int("status", status),
+			zap.Int("bytes", rec.bytes),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("username", entry.username),
+		)
+	}
+}
+
+// routeTemplate возвращает шаблон маршрута вида /api/tenders/{tenderId}/status, чтобы идентификаторы не размножали строки журнала.
+// Если маршрут не найден, используется путь запроса.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"avito.go/internal/models"
+	"avito.go/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zap.InfoLevel)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+	return logs
+}
+
+func TestAccessLog(t *testing.T) {
+	logs := observeLogs(t)
+	auth := newTestAuth(t, "test", time.Hour, false)
+	token, _, err := auth.BuildToken(models.User{ID: "42", Username: "user1"})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/status", NewAccessLog(1).Handler(auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("Published"))
+	})))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/t1/status", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, http.MethodPut, fields["method"])
+	assert.Equal(t, "/api/tenders/{tenderId}/status", fields["route"])
+	assert.EqualValues(t, http.StatusCreated, fields["status"])
+	assert.EqualValues(t, len("Published"), fields["bytes"])
+	assert.Equal(t, "user1", fields["username"])
+	assert.GreaterOrEqual(t, fields["latency"], 5*time.Millisecond)
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	logs := observeLogs(t)
+	accessLog := NewAccessLog(0.5)
+	accessLog.sample = func() float64 { return 0.7 }
+	auth := newTestAuth(t, "test", time.Hour, true)
+
+	// В legacy-режиме сотрудника из параметров запроса записывает middleware, а не обработчик
+	handler := accessLog.Handler(auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ping?username=legacy", nil))
+	assert.Equal(t, 0, logs.Len())
+
+	// Ошибки попадают в журнал независимо от выборки
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ping?username=legacy&fail=1", nil))
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.EqualValues(t, http.StatusNotFound, fields["status"])
+	assert.Equal(t, "/api/ping", fields["route"])
+	assert.Equal(t, "legacy", fields["username"])
+
+	accessLog.sample = func() float64 { return 0.2 }
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	assert.Equal(t, 2, logs.Len())
+}
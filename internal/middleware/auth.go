@@ -1,43 +1,180 @@
 package middleware
 
 import (
+	"avito.go/internal/models"
+	"avito.go/pkg/requestid"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
-	"math/rand"
-	"strconv"
+	"net/http"
+	"strings"
+	"time"
 )
 
+var ErrInvalidToken = errors.New("invalid token")
+
+// MinSecretLength - минимальная длина ключа подписи токенов в байтах; ключ короче подбирается перебором
+const MinSecretLength = 32
+
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
 }
 
-func BuildToken(secretKey string) (string, error) {
-	userID := rand.Int()
+type ErrorResponse struct {
+	Reason    string `json:"reason"`
+	RequestID string `json:"requestId,omitempty"` // Идентификатор запроса из X-Request-ID, по нему ошибку можно найти в логах
+}
+
+type ctxKey int
+
+const (
+	userKey ctxKey = iota
+	accessKey
+)
+
+// Auth выпускает и проверяет токены сотрудников.
+// В режиме Legacy запросы без заголовка Authorization пропускаются, и обработчики берут username из параметров запроса.
+type Auth struct {
+	Secret   string
+	TokenTTL time.Duration
+	Legacy   bool
+}
+
+// NewAuth отказывается работать с коротким ключом: с пустым ключом jwt подписывает токены, и их может подделать кто угодно
+func NewAuth(secret string, tokenTTL time.Duration, legacy bool) (*Auth, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("auth secret must be at least %d bytes", MinSecretLength)
+	}
+	return &Auth{Secret: secret, TokenTTL: tokenTTL, Legacy: legacy}, nil
+}
+
+// BuildToken подписывает токен для сотрудника из таблицы employee
+func (a *Auth) BuildToken(user models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(a.TokenTTL)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		RegisteredClaims: jwt.RegisteredClaims{},
-		UserID:           strconv.Itoa(userID),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		UserID:   user.ID,
+		Username: user.Username,
 	})
 
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := token.SignedString([]byte(a.Secret))
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
-	return tokenString, nil
+	return tokenString, expiresAt, nil
 }
 
-func GetUserID(tokenString string) string {
+func (a *Auth) ParseToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	token, _ := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(a.Secret), nil
 	})
-	if !token.Valid {
-		fmt.Println("Token is not valid")
-		return "error"
+	if err != nil || !token.Valid || claims.Username == "" {
+		return nil, ErrInvalidToken
 	}
+	return claims, nil
+}
+
+// Authenticate проверяет заголовок "Authorization: Bearer <token>" и кладёт сотрудника в контекст запроса
+func (a *Auth) Authenticate(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" && a.Legacy {
+			// Обработчик возьмёт username из параметров запроса, его же пишем в журнал доступа
+			recordUsername(r.Context(), r.URL.Query().Get("username"))
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			unauthorized(w, r, "The authorization token is missing.")
+			return
+		}
+		claims, err := a.ParseToken(tokenString)
+		if err != nil {
+			unauthorized(w, r, "The authorization token is invalid or expired.")
+			return
+		}
 
-	fmt.Println("Token is valid")
-	return claims.UserID
+		user := models.User{ID: claims.UserID, Username: claims.Username}
+		h.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	}
+}
+
+// Optional - для публичных маршрутов: запрос без токена проходит анонимно, а с токеном проверяется как в Authenticate.
+// В legacy-режиме username из параметров запроса считается сотрудником, как и в обработчиках закрытых маршрутов.
+func (a *Auth) Optional(h http.HandlerFunc) http.HandlerFunc {
+	authenticated := a.Authenticate(h)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			authenticated(w, r)
+			return
+		}
+		if username := r.URL.Query().Get("username"); a.Legacy && username != "" {
+			r = r.WithContext(WithUser(r.Context(), models.User{Username: username}))
+		}
+		h.ServeHTTP(w, r)
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+
+	response := ErrorResponse{Reason: reason, RequestID: requestid.FromContext(r.Context())}
+	json.NewEncoder(w).Encode(response)
+}
+
+func WithUser(ctx context.Context, user models.User) context.Context {
+	recordUsername(ctx, user.Username)
+	return context.WithValue(ctx, userKey, user)
+}
+
+// recordUsername записывает сотрудника в строку журнала доступа, если запрос через него проходит
+func recordUsername(ctx context.Context, username string) {
+	if entry, ok := ctx.Value(accessKey).(*accessEntry); ok && username != "" {
+		entry.username = username
+	}
+}
+
+// withAccessEntry кладёт в контекст строку журнала доступа, чтобы middleware аутентификации могли записать в неё сотрудника
+func withAccessEntry(ctx context.Context, entry *accessEntry) context.Context {
+	return context.WithValue(ctx, accessKey, entry)
+}
+
+func UserFromContext(ctx context.Context) (models.User, bool) {
+	user, ok := ctx.Value(userKey).(models.User)
+	return user, ok
+}
+
+// AuthUsername возвращает имя аутентифицированного сотрудника, а в legacy-режиме - значение из запроса
+func AuthUsername(r *http.Request, fallback string) string {
+	if user, ok := UserFromContext(r.Context()); ok {
+		return user.Username
+	}
+	return fallback
+}
+
+// AuthUserID возвращает идентификатор аутентифицированного сотрудника, а в legacy-режиме - значение из запроса
+func AuthUserID(r *http.Request, fallback string) string {
+	if user, ok := UserFromContext(r.Context()); ok {
+		return user.ID
+	}
+	return fallback
 }
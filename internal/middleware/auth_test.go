@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"avito.go/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAuth дополняет name до ключа допустимой длины, разные name дают разные ключи
+func newTestAuth(t *testing.T, name string, tokenTTL time.Duration, legacy bool) *Auth {
+	auth, err := NewAuth(name+strings.Repeat("-", MinSecretLength), tokenTTL, legacy)
+	require.NoError(t, err)
+	return auth
+}
+
+func TestNewAuth_ShortSecret(t *testing.T) {
+	for _, secret := range []string{"", "key", strings.Repeat("k", MinSecretLength-1)} {
+		_, err := NewAuth(secret, time.Hour, false)
+		assert.Error(t, err, secret)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := newTestAuth(t, "test", time.Hour, false)
+	token, _, err := auth.BuildToken(models.User{ID: "42", Username: "user1"})
+	assert.NoError(t, err)
+
+	var got string
+	handler := auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		got = AuthUsername(r, "from-query")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/my?username=intruder", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "user1", got)
+}
+
+func TestAuthenticate_Rejected(t *testing.T) {
+	auth := newTestAuth(t, "test", time.Hour, false)
+	forged, _, err := newTestAuth(t, "other", time.Hour, false).BuildToken(models.User{ID: "42", Username: "user1"})
+	assert.NoError(t, err)
+	expired, _, err := newTestAuth(t, "test", -time.Minute, false).BuildToken(models.User{ID: "42", Username: "user1"})
+	assert.NoError(t, err)
+
+	handler := auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	})
+
+	for _, header := range []string{"", "Bearer " + forged, "Bearer " + expired, "Basic dXNlcjE6cGFzcw=="} {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenders/my", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, header)
+	}
+}
+
+func TestAuthenticate_Legacy(t *testing.T) {
+	auth := newTestAuth(t, "test", time.Hour, true)
+
+	var got string
+	handler := auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		got = AuthUsername(r, r.URL.Query().Get("username"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/my?username=user1", nil)
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "user1", got)
+}
+
+func TestOptional(t *testing.T) {
+	strict := newTestAuth(t, "test", time.Hour, false)
+	token, _, err := strict.BuildToken(models.User{ID: "42", Username: "user1"})
+	assert.NoError(t, err)
+
+	var got string
+	var called bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		user, _ := UserFromContext(r.Context())
+		got = user.Username
+	}
+
+	cases := []struct {
+		auth   *Auth
+		url    string
+		header string
+		code   int
+		want   string
+	}{
+		{strict, "/api/tenders?username=intruder", "", http.StatusOK, ""},
+		{strict, "/api/tenders", "Bearer " + token, http.StatusOK, "user1"},
+		{strict, "/api/tenders", "Bearer broken", http.StatusUnauthorized, ""},
+		{newTestAuth(t, "test", time.Hour, true), "/api/tenders?username=user2", "", http.StatusOK, "user2"},
+	}
+	for _, c := range cases {
+		called, got = false, ""
+		req := httptest.NewRequest(http.MethodGet, c.url, nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		rr := httptest.NewRecorder()
+		c.auth.Optional(handler)(rr, req)
+
+		assert.Equal(t, c.code, rr.Code, c.url)
+		assert.Equal(t, c.code == http.StatusOK, called, c.url)
+		assert.Equal(t, c.want, got, c.url)
+	}
+}
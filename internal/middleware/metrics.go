@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"avito.go/internal/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics считает запросы и время их обработки по шаблону маршрута, методу и коду ответа
+func Metrics(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w}
+
+		h.ServeHTTP(rec, r)
+
+		labels := []string{routeTemplate(r), r.Method, strconv.Itoa(rec.statusCode())}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"avito.go/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/status", Metrics(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	counter := metrics.HTTPRequests.WithLabelValues("/api/bids/{bidId}/status", http.MethodGet, "404")
+	before := testutil.ToFloat64(counter)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/bids/b1/status", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/bids/b2/status", nil))
+
+	assert.Equal(t, before+2, testutil.ToFloat64(counter))
+}
@@ -1,46 +1,23 @@
 package middleware
 
 import (
-	"avito.go/pkg/logger"
+	"avito.go/pkg/requestid"
+	"avito.go/pkg/uuid"
 	"compress/gzip"
-	"errors"
-	"go.uber.org/zap"
-	"log"
 	"net/http"
 	"strings"
-	"time"
 )
 
 func Middleware(h http.HandlerFunc) http.HandlerFunc {
 	foo := func(w http.ResponseWriter, r *http.Request) {
-		Time := time.Now()
-		Duration := time.Since(Time)
-		logger.Log.Info(
-			"INFO",
-			zap.String("method", r.Method),
-			zap.String("time", Duration.String()),
-			zap.String("URI", r.RequestURI),
-		)
-
-		cookie, err := r.Cookie("default")
-		if err != nil {
-			switch {
-			case errors.Is(err, http.ErrNoCookie):
-				value, err := BuildToken(secretKey)
-				if err != nil {
-					log.Println("error building token", err)
-				}
-				cookie = &http.Cookie{
-					Name:  "default",
-					Value: value,
-				}
-				http.SetCookie(w, cookie)
-			default:
-				log.Println(err)
-				w.WriteHeader(http.StatusUnauthorized)
-			}
+		// Идентификатор запроса берётся из X-Request-ID или генерируется; он попадает в логи, журнал аудита,
+		// ошибки хранилища и возвращается клиенту в том же заголовке
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = uuid.GenerateCorrelationID()
 		}
-		w.Header().Set("Authorization", cookie.Value)
+		r = r.WithContext(requestid.NewContext(r.Context(), id))
+		w.Header().Set(requestid.Header, id)
 
 		if !strings.Contains(r.Header.Get("Accept-Encoding"), `gzip`) {
 			h.ServeHTTP(w, r)
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_RequestID(t *testing.T) {
+	var got string
+	handler := Middleware(func(w http.ResponseWriter, r *http.Request) {
+		got = requestid.FromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.Header.Set(requestid.Header, "gateway-42")
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, "gateway-42", got)
+	assert.Equal(t, "gateway-42", rr.Header().Get(requestid.Header))
+
+	// Некорректный идентификатор клиента заменяется сгенерированным
+	req = httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.Header.Set(requestid.Header, "bad id")
+	rr = httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.NotEqual(t, "bad id", got)
+	assert.True(t, requestid.Valid(got))
+	assert.Equal(t, got, rr.Header().Get(requestid.Header))
+}
+
+func TestMiddleware_RequestIDInErrorBody(t *testing.T) {
+	auth := newTestAuth(t, "test", time.Hour, false)
+	handler := Middleware(auth.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/my", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	var response ErrorResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, "req-1", response.RequestID)
+}
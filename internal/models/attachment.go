@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Attachment - файл, приложенный к тендеру или предложению. Содержимое лежит в хранилище файлов по ключу ID,
+// Version - версия сущности на момент загрузки, Checksum - SHA-256 содержимого в hex.
+type Attachment struct {
+	ID          string    `json:"id"`
+	EntityType  string    `json:"entityType"` // tender или bid, как в журнале аудита
+	EntityID    string    `json:"entityId"`
+	Version     int       `json:"version"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	Author      string    `json:"author"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Действия журнала аудита
+const (
+	AuditCreate   = "create"
+	AuditEdit     = "edit"
+	AuditStatus   = "status"
+	AuditRollback = "rollback"
+	AuditDecision = "decision"
+	AuditFeedback = "feedback"
+	AuditAttach   = "attach"
+)
+
+// Типы сущностей журнала аудита
+const (
+	AuditTender = "tender"
+	AuditBid    = "bid"
+)
+
+// AuditActorSystem - автор изменений, которые сервис делает сам, например закрытие тендера по сроку
+const AuditActorSystem = "system"
+
+// AuditEvent - запись журнала: кто, что и с какой версией сделал.
+// OrganizationID - организация тендера, к которому относится сущность; по ней ограничен доступ к журналу.
+type AuditEvent struct {
+	ID             string    `json:"id"`
+	Actor          string    `json:"actor"`
+	Action         string    `json:"action"`
+	EntityType     string    `json:"entityType"`
+	EntityID       string    `json:"entityId"`
+	OrganizationID string    `json:"organizationId"`
+	VersionBefore  int       `json:"versionBefore"` // 0 для созданной сущности
+	VersionAfter   int       `json:"versionAfter"`
+	CorrelationID  string    `json:"correlationId,omitempty"` // Идентификатор HTTP-запроса, в котором произошло изменение
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// AuditFilter - параметры выборки журнала организации; пустые поля не фильтруют
+type AuditFilter struct {
+	OrganizationID string
+	EntityType     string
+	EntityID       string
+	Action         string
+	Actor          string
+}
+
+// OrderByCreatedAtDesc - упорядочивание журнала: created_at DESC, id DESC
+const OrderByCreatedAtDesc = "createdAt:desc"
+
+// AuditKey - ключ события в упорядочивании OrderByCreatedAtDesc
+func AuditKey(event AuditEvent) []string {
+	return []string{FormatKeyTime(event.CreatedAt), event.ID}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// FieldChange - изменение одного поля между двумя версиями сущности
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type VersionDiff struct {
+	FromVersion int           `json:"fromVersion"`
+	ToVersion   int           `json:"toVersion"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+// DiffTenders сравнивает редактируемые поля двух версий тендера
+func DiffTenders(from, to Tender) VersionDiff {
+	diff := VersionDiff{FromVersion: from.Version, ToVersion: to.Version, Changes: []FieldChange{}}
+	diff.add("name", from.Name, to.Name)
+	diff.add("description", from.Description, to.Description)
+	diff.add("serviceType", from.ServiceType, to.ServiceType)
+	diff.add("status", from.Status, to.Status)
+	diff.add("organizationId", from.OrganizationID, to.OrganizationID)
+	diff.addTime("submissionDeadline", from.SubmissionDeadline, to.SubmissionDeadline)
+	diff.addTime("decisionDeadline", from.DecisionDeadline, to.DecisionDeadline)
+	return diff
+}
+
+// DiffBids сравнивает редактируемые поля двух версий предложения
+func DiffBids(from, to Bid) VersionDiff {
+	diff := VersionDiff{FromVersion: int(from.Version), ToVersion: int(to.Version), Changes: []FieldChange{}}
+	diff.add("name", from.Name, to.Name)
+	diff.add("description", from.Description, to.Description)
+	diff.add("status", from.Status, to.Status)
+	diff.add("tenderId", from.TenderID, to.TenderID)
+	diff.add("authorType", from.AuthorType, to.AuthorType)
+	diff.add("authorId", from.AuthorID, to.AuthorID)
+	return diff
+}
+
+func (d *VersionDiff) add(field string, from, to string) {
+	if from != to {
+		d.Changes = append(d.Changes, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+// addTime сравнивает необязательные моменты времени; nil означает, что срок не задан
+func (d *VersionDiff) addTime(field string, from, to *time.Time) {
+	if from == nil && to == nil {
+		return
+	}
+	if from != nil && to != nil && from.Equal(*to) {
+		return
+	}
+	d.Changes = append(d.Changes, FieldChange{Field: field, From: from, To: to})
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Поля сортировки списка тендеров
+const (
+	TenderSortName      = "name"
+	TenderSortCreatedAt = "createdAt"
+	TenderSortUpdatedAt = "updatedAt"
+)
+
+// TenderFilter - параметры выборки тендеров для GET /api/tenders
+type TenderFilter struct {
+	Limit       int
+	Offset      int
+	After       []string // Ключ из курсора, см. Order и Key
+	ServiceType []string
+	Query       string // Полнотекстовый поиск по названию и описанию; при заданном Query выдача упорядочена по релевантности
+
+	// Status по умолчанию - только Published; остальные статусы видны лишь ответственным за организацию тендера
+	Status         []string
+	OrganizationID string
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	UpdatedFrom    time.Time
+	UpdatedTo      time.Time
+
+	Sort     string // name, createdAt или updatedAt; пусто - по релевантности при поиске, иначе по названию
+	Desc     bool
+	Username string // Сотрудник, от имени которого запрошен список; пусто для анонимного запроса
+}
@@ -0,0 +1,35 @@
+package models_test
+
+import (
+	"avito.go/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTenders_DeadlineOnly(t *testing.T) {
+	submission := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	moved := submission.Add(24 * time.Hour)
+	from := models.Tender{Name: "Tender", Status: "Published", Version: 1, SubmissionDeadline: &submission}
+	to := models.Tender{Name: "Tender", Status: "Published", Version: 2, SubmissionDeadline: &moved, DecisionDeadline: &moved}
+
+	diff := models.DiffTenders(from, to)
+
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 2, diff.ToVersion)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "submissionDeadline", From: &submission, To: &moved},
+		{Field: "decisionDeadline", From: (*time.Time)(nil), To: &moved},
+	}, diff.Changes)
+}
+
+func TestDiffTenders_SameDeadline(t *testing.T) {
+	deadline := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	// Тот же момент в другой зоне - не изменение
+	local := deadline.In(time.FixedZone("MSK", 3*60*60))
+	from := models.Tender{Version: 1, SubmissionDeadline: &deadline}
+	to := models.Tender{Version: 2, SubmissionDeadline: &local}
+
+	assert.Empty(t, models.DiffTenders(from, to).Changes)
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type Organization struct {
+	ID          string    `json:"id" validate:"required,max=100"`             // Уникальный идентификатор организации
+	Name        string    `json:"name" validate:"required,max=100"`           // Полное название организации
+	Description string    `json:"description" validate:"max=500"`             // Описание организации
+	Type        string    `json:"type" validate:"omitempty,oneof=IE LLC JSC"` // Организационно-правовая форма
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
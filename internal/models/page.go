@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Page - параметры постраничной выборки.
+// After - ключ последней записи предыдущей страницы из курсора; при заданном After Offset не учитывается.
+type Page struct {
+	Limit  int
+	Offset int
+	After  []string
+}
+
+// Упорядочивания списков, для которых выдаются курсоры
+const (
+	OrderByName      = "name"      // name, id
+	OrderByCreatedAt = "createdAt" // created_at, id
+	OrderByRank      = "rank"      // rank DESC, name, id
+)
+
+// TenderKey - ключ тендера в упорядочивании OrderByName
+func TenderKey(tender Tender) []string {
+	return []string{tender.Name, tender.ID}
+}
+
+// BidKey - ключ предложения в упорядочивании OrderByName
+func BidKey(bid Bid) []string {
+	return []string{bid.Name, bid.ID}
+}
+
+// FeedbackKey - ключ отзыва в упорядочивании OrderByCreatedAt
+func FeedbackKey(feedback FeedBack) []string {
+	return []string{FormatKeyTime(feedback.CreatedAt), feedback.ID}
+}
+
+// FormatKeyTime и ParseKeyTime переводят даты ключа в строку и обратно без потери точности
+func FormatKeyTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func ParseKeyTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// Order - имя упорядочивания выборки тендеров, в котором выдан курсор
+func (f TenderFilter) Order() string {
+	order := f.Sort
+	switch {
+	case order == "" && f.Query != "":
+		return OrderByRank
+	case order == "":
+		order = TenderSortName
+	}
+	if f.Desc {
+		return order + ":desc"
+	}
+	return order
+}
+
+// Key - ключ тендера в упорядочивании фильтра; порядок полей совпадает с ORDER BY
+func (f TenderFilter) Key(tender Tender) []string {
+	switch {
+	case f.Sort == TenderSortCreatedAt:
+		return []string{FormatKeyTime(tender.CreatedAt), tender.ID}
+	case f.Sort == TenderSortUpdatedAt:
+		return []string{FormatKeyTime(tender.UpdatedAt), tender.ID}
+	case f.Sort == "" && f.Query != "":
+		return []string{strconv.FormatFloat(tender.Rank, 'g', -1, 64), tender.Name, tender.ID}
+	}
+	return TenderKey(tender)
+}
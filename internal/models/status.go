@@ -0,0 +1,29 @@
+package models
+
+import "slices"
+
+// TenderTransitions - допустимые переходы статусов тендера: текущий статус -> возможные следующие.
+// Closed - конечный статус.
+var TenderTransitions = map[string][]string{
+	"Created":   {"Published", "Closed"},
+	"Published": {"Closed"},
+	"Closed":    {},
+}
+
+// BidTransitions - допустимые переходы статусов предложения.
+// Approved и Rejected выставляются решениями ответственных, Canceled, Approved и Rejected - конечные.
+var BidTransitions = map[string][]string{
+	"Created":   {"Published", "Canceled"},
+	"Published": {"Canceled", "Approved", "Rejected"},
+	"Canceled":  {},
+	"Approved":  {},
+	"Rejected":  {},
+}
+
+func CanTransitionTender(from, to string) bool {
+	return slices.Contains(TenderTransitions[from], to)
+}
+
+func CanTransitionBid(from, to string) bool {
+	return slices.Contains(BidTransitions[from], to)
+}
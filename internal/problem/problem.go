@@ -0,0 +1,155 @@
+package problem
+
+import (
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/etag"
+	"avito.go/pkg/logger"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// ContentType - тип тела ответа с ошибкой по RFC 7807
+const ContentType = "application/problem+json"
+
+// Error - ошибка API: HTTP-статус, машиночитаемый код и сообщение для клиента.
+// Err - исходная ошибка, она пишется в лог и клиенту не возвращается.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrInvalidParameters  = &Error{Status: http.StatusBadRequest, Code: "invalid_parameters", Message: "The request parameters are incorrect."}
+	ErrInvalidBody        = &Error{Status: http.StatusBadRequest, Code: "invalid_body", Message: "The request body is incorrect."}
+	ErrInvalidIfMatch     = &Error{Status: http.StatusBadRequest, Code: "invalid_if_match", Message: "The If-Match header is incorrect."}
+	ErrUnauthorized       = &Error{Status: http.StatusUnauthorized, Code: "user_not_found", Message: "The user does not exist or is invalid."}
+	ErrForbidden          = &Error{Status: http.StatusForbidden, Code: "forbidden", Message: "Insufficient rights to perform the action."}
+	ErrTenderNotFound     = &Error{Status: http.StatusNotFound, Code: "tender_not_found", Message: "The tender does not exist."}
+	ErrBidNotFound        = &Error{Status: http.StatusNotFound, Code: "bid_not_found", Message: "The bid does not exist."}
+	ErrVersionNotFound    = &Error{Status: http.StatusNotFound, Code: "version_not_found", Message: "The version does not exist."}
+	ErrReviewsNotFound    = &Error{Status: http.StatusNotFound, Code: "reviews_not_found", Message: "The reviews do not exist."}
+	ErrAttachmentNotFound = &Error{Status: http.StatusNotFound, Code: "attachment_not_found", Message: "The attachment does not exist."}
+	ErrOrgNotFound        = &Error{Status: http.StatusNotFound, Code: "organization_not_found", Message: "The organization does not exist."}
+	ErrNotResponsible     = &Error{Status: http.StatusNotFound, Code: "responsible_not_found", Message: "The employee is not responsible for the organization."}
+	ErrDecisionMade       = &Error{Status: http.StatusConflict, Code: "decision_made", Message: "The decision has already been made."}
+	ErrDeadlinePassed     = &Error{Status: http.StatusConflict, Code: "deadline_passed", Message: "The submission deadline for the tender has passed."}
+	ErrInvalidTransition  = &Error{Status: http.StatusConflict, Code: "invalid_transition", Message: "The status transition is not allowed."}
+	ErrAlreadyResponsible = &Error{Status: http.StatusConflict, Code: "already_responsible", Message: "The employee is already responsible for the organization."}
+	ErrLastResponsible    = &Error{Status: http.StatusConflict, Code: "last_responsible", Message: "The organization must keep at least one responsible employee."}
+	ErrVersionMismatch    = &Error{Status: http.StatusPreconditionFailed, Code: "version_mismatch", Message: "The entity has been modified by another request."}
+	ErrAttachmentTooLarge = &Error{Status: http.StatusRequestEntityTooLarge, Code: "attachment_too_large", Message: "The attachment exceeds the maximum allowed size."}
+	ErrChecksumMismatch   = &Error{Status: http.StatusBadRequest, Code: "checksum_mismatch", Message: "The attachment checksum does not match its content."}
+	ErrInternal           = &Error{Status: http.StatusInternalServerError, Code: "internal", Message: "Internal server error."}
+)
+
+// domainProblems сопоставляет ошибкам хранилища из storage.DomainErrors ошибки API
+var domainProblems = map[error]*Error{
+	storage.ErrRights:             ErrForbidden,
+	storage.ErrNoUser:             ErrUnauthorized,
+	storage.ErrNoTender:           ErrTenderNotFound,
+	storage.ErrNoBid:              ErrBidNotFound,
+	storage.ErrNoVersion:          ErrVersionNotFound,
+	storage.ErrNoReviews:          ErrReviewsNotFound,
+	storage.ErrNoAttachment:       ErrAttachmentNotFound,
+	storage.ErrNoOrganization:     ErrOrgNotFound,
+	storage.ErrDecisionMade:       ErrDecisionMade,
+	storage.ErrDeadlinePassed:     ErrDeadlinePassed,
+	storage.ErrInvalidTransition:  ErrInvalidTransition,
+	storage.ErrAlreadyResponsible: ErrAlreadyResponsible,
+	storage.ErrLastResponsible:    ErrLastResponsible,
+	storage.ErrNotResponsible:     ErrNotResponsible,
+	storage.ErrVersionMismatch:    ErrVersionMismatch,
+}
+
+// requestErrors сопоставляет ошибки разбора параметров запроса ошибкам API
+var requestErrors = []struct {
+	err     error
+	problem *Error
+}{
+	{cursor.ErrInvalid, ErrInvalidParameters},
+	{etag.ErrInvalid, ErrInvalidIfMatch},
+	{etag.ErrWeak, ErrVersionMismatch},
+}
+
+// MethodNotSupported - ошибка для запроса с методом, который обработчик не принимает
+func MethodNotSupported(method string) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: "method_not_supported", Message: "Only " + method + " requests are supported"}
+}
+
+// From приводит ошибку к ошибке API. Неизвестные ошибки становятся ErrInternal с исходной ошибкой в Err.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	for _, domain := range storage.DomainErrors {
+		if apiErr, ok := domainProblems[domain]; ok && errors.Is(err, domain) {
+			return apiErr
+		}
+	}
+	for _, request := range requestErrors {
+		if errors.Is(err, request.err) {
+			return request.problem
+		}
+	}
+	// Идентификатор, который Postgres не смог привести к uuid, - ошибка клиента, а не сервера
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+		return ErrInvalidParameters
+	}
+	return &Error{Status: ErrInternal.Status, Code: ErrInternal.Code, Message: ErrInternal.Message, Err: err}
+}
+
+// Problem - тело ответа с ошибкой в формате RFC 7807.
+// Code - машиночитаемый код ошибки, Reason дублирует Detail для клиентов, которые читают поле reason.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	Reason    string `json:"reason"`
+	RequestID string `json:"requestId,omitempty"` // Идентификатор запроса из X-Request-ID, по нему ошибку можно найти в логах
+}
+
+// Write пишет ошибку в ответ как application/problem+json. Ошибки сервера логируются, их текст клиенту не отдаётся.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := From(err)
+	if apiErr.Status >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error("request failed", zap.String("path", r.URL.Path), zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(apiErr.Status)
+
+	response := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(apiErr.Status),
+		Status:    apiErr.Status,
+		Detail:    apiErr.Message,
+		Instance:  r.URL.Path,
+		Code:      apiErr.Code,
+		Reason:    apiErr.Message,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,93 @@
+package problem_test
+
+import (
+	"avito.go/internal/problem"
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		err  error
+		want *problem.Error
+	}{
+		{storage.ErrRights, problem.ErrForbidden},
+		{storage.ErrNoUser, problem.ErrUnauthorized},
+		{storage.ErrNoTender, problem.ErrTenderNotFound},
+		{storage.ErrNoBid, problem.ErrBidNotFound},
+		{storage.ErrNoVersion, problem.ErrVersionNotFound},
+		{storage.ErrVersionMismatch, problem.ErrVersionMismatch},
+		{cursor.ErrInvalid, problem.ErrInvalidParameters},
+		// ошибки хранилища приходят обёрнутыми идентификатором запроса
+		{fmt.Errorf("request 42: %w", storage.ErrNoBid), problem.ErrBidNotFound},
+		{fmt.Errorf("error executing query: %w", &pgconn.PgError{Code: "22P02"}), problem.ErrInvalidParameters},
+		{problem.ErrInvalidBody, problem.ErrInvalidBody},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, problem.From(tt.err), tt.err.Error())
+	}
+}
+
+func TestFrom_DomainErrors(t *testing.T) {
+	// Каждая ожидаемая ошибка хранилища должна стать ошибкой клиента, а не 500
+	for _, err := range storage.DomainErrors {
+		got := problem.From(err)
+		assert.Less(t, got.Status, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func TestFrom_Unknown(t *testing.T) {
+	cause := errors.New("connection reset")
+
+	got := problem.From(cause)
+
+	assert.Equal(t, http.StatusInternalServerError, got.Status)
+	assert.Equal(t, "internal", got.Code)
+	assert.ErrorIs(t, got, cause)
+	assert.Nil(t, problem.ErrInternal.Err)
+}
+
+func TestWrite(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/1/status", nil)
+	req = req.WithContext(requestid.NewContext(req.Context(), "req-1"))
+	rr := httptest.NewRecorder()
+
+	problem.Write(rr, req, fmt.Errorf("request req-1: %w", storage.ErrNoBid))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+	var response problem.Problem
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, problem.Problem{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "The bid does not exist.",
+		Instance:  "/api/bids/1/status",
+		Code:      "bid_not_found",
+		Reason:    "The bid does not exist.",
+		RequestID: "req-1",
+	}, response)
+}
+
+func TestWrite_InternalHidesCause(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders", nil)
+	rr := httptest.NewRecorder()
+
+	problem.Write(rr, req, errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "password")
+}
@@ -2,35 +2,74 @@ package routes
 
 import (
 	"avito.go/internal/app"
+	"avito.go/internal/metrics"
 	"avito.go/internal/middleware"
 	"github.com/gorilla/mux"
+	"net/http"
 )
 
-func NewRouter(App app.App) *mux.Router {
+func NewRouter(App app.App, auth *middleware.Auth, accessLog *middleware.AccessLog) *mux.Router {
 	router := mux.NewRouter()
 
-	router.HandleFunc("/api/ping", middleware.Middleware(App.CheckerController.CheckServer)).Methods("GET")
+	// public - общая обвязка всех маршрутов: идентификатор запроса, сжатие, трассировка, журнал доступа и метрики
+	public := func(h http.HandlerFunc) http.HandlerFunc {
+		return middleware.Middleware(middleware.Tracing(accessLog.Handler(middleware.Metrics(h))))
+	}
+	// private - маршруты, доступные только с токеном сотрудника (или с username в legacy-режиме)
+	private := func(h http.HandlerFunc) http.HandlerFunc {
+		return public(auth.Authenticate(h))
+	}
 
-	router.HandleFunc("/api/tenders", middleware.Middleware(App.TenderController.TendersInfo)).Methods("GET")
-	router.HandleFunc("/api/tenders/my", middleware.Middleware(App.TenderController.TendersMy)).Methods("GET")
-	router.HandleFunc("/api/tenders/new", middleware.Middleware(App.TenderController.CreateTender)).Methods("POST")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.HandleFunc("/api/ping", public(App.CheckerController.CheckServer)).Methods("GET")
+	router.HandleFunc("/api/health/live", public(App.CheckerController.HealthLive)).Methods("GET")
+	router.HandleFunc("/api/health/ready", public(App.CheckerController.HealthReady)).Methods("GET")
+	router.HandleFunc("/api/auth/token", public(App.AuthController.IssueToken)).Methods("POST")
 
-	router.HandleFunc("/api/tenders/{tenderId}/status", middleware.Middleware(App.TenderController.TenderStatus)).Methods("GET")
-	router.HandleFunc("/api/tenders/{tenderId}/status", middleware.Middleware(App.TenderController.TenderUpdateStatus)).Methods("PUT")
-	router.HandleFunc("/api/tenders/{tenderId}/edit", middleware.Middleware(App.TenderController.TenderEdit)).Methods("PATCH")
-	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", middleware.Middleware(App.TenderController.RollbackTender)).Methods("PUT")
+	router.HandleFunc("/api/tenders", public(auth.Optional(App.TenderController.TendersInfo))).Methods("GET")
+	router.HandleFunc("/api/tenders/my", private(App.TenderController.TendersMy)).Methods("GET")
+	router.HandleFunc("/api/tenders/new", private(App.TenderController.CreateTender)).Methods("POST")
 
-	router.HandleFunc("/api/bids/new", middleware.Middleware(App.BidController.CreateBid)).Methods("POST")
-	router.HandleFunc("/api/bids/my", middleware.Middleware(App.BidController.BidsMy)).Methods("GET")
-	router.HandleFunc("/api/bids/{tenderId}/list", middleware.Middleware(App.BidController.BidsTenderList)).Methods("GET")
-	router.HandleFunc("/api/bids/{bidId}/status", middleware.Middleware(App.BidController.BidStatus)).Methods("GET")
-	router.HandleFunc("/api/bids/{bidId}/status", middleware.Middleware(App.BidController.BidUpdateStatus)).Methods("PUT")
-	router.HandleFunc("/api/bids/{bidId}/edit", middleware.Middleware(App.BidController.BidEdit)).Methods("PATCH")
-	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", middleware.Middleware(App.BidController.RollbackTender)).Methods("PUT")
-	router.HandleFunc("/api/bids/{bidId}/submit_decision", middleware.Middleware(App.BidController.BidSubmitDecision)).Methods("PUT")
+	router.HandleFunc("/api/tenders/{tenderId}/status", private(App.TenderController.TenderStatus)).Methods("GET")
+	router.HandleFunc("/api/tenders/{tenderId}/status", private(App.TenderController.TenderUpdateStatus)).Methods("PUT")
+	router.HandleFunc("/api/tenders/{tenderId}/edit", private(App.TenderController.TenderEdit)).Methods("PATCH")
+	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", private(App.TenderController.RollbackTender)).Methods("PUT")
+	router.HandleFunc("/api/tenders/{tenderId}/versions", private(App.TenderController.TenderVersions)).Methods("GET")
+	router.HandleFunc("/api/tenders/{tenderId}/diff", private(App.TenderController.TenderDiff)).Methods("GET")
+	router.HandleFunc("/api/tenders/{tenderId}/attachments", private(App.AttachmentController.TenderAttachments)).Methods("GET")
+	router.HandleFunc("/api/tenders/{tenderId}/attachments", private(App.AttachmentController.TenderAttachmentUpload)).Methods("POST")
 
-	router.HandleFunc("/api/bids/{bidId}/feedback", middleware.Middleware(App.BidController.BidFeedback)).Methods("PUT")
-	router.HandleFunc("/api/bids/{tenderId}/reviews", middleware.Middleware(App.BidController.BidsReviews)).Methods("GET")
+	router.HandleFunc("/api/bids/new", private(App.BidController.CreateBid)).Methods("POST")
+	router.HandleFunc("/api/bids/my", private(App.BidController.BidsMy)).Methods("GET")
+	router.HandleFunc("/api/bids/{tenderId}/list", private(App.BidController.BidsTenderList)).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/status", private(App.BidController.BidStatus)).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/status", private(App.BidController.BidUpdateStatus)).Methods("PUT")
+	router.HandleFunc("/api/bids/{bidId}/edit", private(App.BidController.BidEdit)).Methods("PATCH")
+	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", private(App.BidController.RollbackTender)).Methods("PUT")
+	router.HandleFunc("/api/bids/{bidId}/versions", private(App.BidController.BidVersions)).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/diff", private(App.BidController.BidDiff)).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/attachments", private(App.AttachmentController.BidAttachments)).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/attachments", private(App.AttachmentController.BidAttachmentUpload)).Methods("POST")
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", private(App.BidController.BidSubmitDecision)).Methods("PUT")
+
+	router.HandleFunc("/api/bids/{bidId}/feedback", private(App.BidController.BidFeedback)).Methods("PUT")
+	router.HandleFunc("/api/bids/{tenderId}/reviews", private(App.BidController.BidsReviews)).Methods("GET")
+
+	router.HandleFunc("/api/attachments/{attachmentId}", private(App.AttachmentController.AttachmentDownload)).Methods("GET")
+
+	router.HandleFunc("/api/organizations", private(App.OrganizationController.OrganizationsList)).Methods("GET")
+	router.HandleFunc("/api/organizations/new", private(App.OrganizationController.CreateOrganization)).Methods("POST")
+	router.HandleFunc("/api/organizations/{organizationId}", private(App.OrganizationController.OrganizationGet)).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}", private(App.OrganizationController.OrganizationDelete)).Methods("DELETE")
+	router.HandleFunc("/api/organizations/{organizationId}/edit", private(App.OrganizationController.OrganizationEdit)).Methods("PATCH")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles", private(App.OrganizationController.OrganizationResponsibles)).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles/{employeeUsername}", private(App.OrganizationController.OrganizationAddResponsible)).Methods("PUT")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles/{employeeUsername}", private(App.OrganizationController.OrganizationRemoveResponsible)).Methods("DELETE")
+	router.HandleFunc("/api/organizations/{organizationId}/audit", private(App.AuditController.AuditEvents)).Methods("GET")
+
+	router.HandleFunc("/api/employees", private(App.EmployeeController.EmployeesList)).Methods("GET")
+	router.HandleFunc("/api/employees/{username}", private(App.EmployeeController.EmployeeProfile)).Methods("GET")
+	router.HandleFunc("/api/employees/{username}/organizations", private(App.EmployeeController.EmployeeOrganizations)).Methods("GET")
 
 	return router
 }
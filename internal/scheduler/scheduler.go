@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"avito.go/pkg/logger"
+	"avito.go/pkg/requestid"
+	"avito.go/pkg/uuid"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TenderCloser - часть хранилища, с которой работает планировщик
+type TenderCloser interface {
+	CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// Scheduler периодически переводит в Closed опубликованные тендеры с истёкшими сроками
+type Scheduler struct {
+	Storage  TenderCloser
+	Interval time.Duration
+	Now      func() time.Time
+}
+
+func New(store TenderCloser, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		Storage:  store,
+		Interval: interval,
+		Now:      time.Now,
+	}
+}
+
+// Run выполняет проход сразу после запуска и затем раз в Interval, пока не отменён ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.CloseExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseExpired - один проход планировщика; ошибки только логируются, следующий проход повторит попытку
+func (s *Scheduler) CloseExpired(ctx context.Context) {
+	// У каждого прохода свой идентификатор, как у HTTP-запроса: он связывает строки логов и события журнала аудита
+	ctx = requestid.NewContext(ctx, uuid.GenerateCorrelationID())
+	closed, err := s.Storage.CloseExpiredTenders(ctx, s.Now().UTC())
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to close expired tenders", zap.Error(err))
+	}
+	if len(closed) > 0 {
+		logger.FromContext(ctx).Info("closed expired tenders", zap.Strings("tenderIds", closed))
+	}
+}
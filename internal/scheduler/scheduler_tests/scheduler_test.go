@@ -0,0 +1,56 @@
+package scheduler_test
+
+import (
+	"avito.go/internal/scheduler"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestCloseExpired_UsesUTC(t *testing.T) {
+	mockStorage := new(MockStorage)
+	s := scheduler.New(mockStorage, time.Minute)
+	local := time.Date(2024, 9, 24, 15, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	s.Now = func() time.Time { return local }
+
+	mockStorage.On("CloseExpiredTenders", mock.Anything, local.UTC()).Return([]string{"t1"}, nil).Once()
+	mockStorage.On("CloseExpiredTenders", mock.Anything, local.UTC()).Return([]string(nil), errors.New("connection refused")).Once()
+
+	s.CloseExpired(context.Background())
+	s.CloseExpired(context.Background())
+
+	mockStorage.AssertExpectations(t)
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	mockStorage := new(MockStorage)
+	mockStorage.On("CloseExpiredTenders", mock.Anything, mock.Anything).Return([]string{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.New(mockStorage, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+	mockStorage.AssertCalled(t, "CloseExpiredTenders", mock.Anything, mock.Anything)
+}
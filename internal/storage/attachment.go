@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"time"
+)
+
+// AddAttachment сохраняет сведения о загруженном файле и привязывает его к текущей версии сущности.
+// Прикладывать файлы к тендеру могут ответственные за его организацию, к предложению - автор и его организация.
+// CanAttach проверяет права на загрузку файла до чтения тела запроса; AddAttachment проверяет их повторно
+func (db *DB) CanAttach(ctx context.Context, entityType, entityID, username string) error {
+	_, err := attachLock(ctx, db, entityType, entityID, username)
+	return err
+}
+
+// attachLock проверяет, что сотрудник может прикрепить файл к сущности, и возвращает блокировку её строки
+func attachLock(ctx context.Context, db *DB, entityType, entityID, username string) (func(ctx context.Context, tx runner, id string) (int, error), error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return nil, ErrNoUser
+	}
+
+	switch entityType {
+	case models.AuditTender:
+		TenderExist, _ := GetTender(ctx, db, entityID)
+		if !TenderExist {
+			return nil, ErrNoTender
+		}
+		check, _ := isUserResponsibleForTender(ctx, db, username, entityID)
+		if !check {
+			return nil, ErrRights
+		}
+		return lockTender, nil
+	case models.AuditBid:
+		BidExist, _ := GetBid(ctx, db, entityID)
+		if !BidExist {
+			return nil, ErrNoBid
+		}
+		author := GetUsernameByID(ctx, db, BidByID(ctx, db, entityID).AuthorID)
+		check, _ := isUserResponsibleToUpdateBid(ctx, db, username, entityID)
+		if author != username && !check {
+			return nil, ErrRights
+		}
+		return lockBid, nil
+	default:
+		return nil, fmt.Errorf("unknown attachment entity type %q", entityType)
+	}
+}
+
+func (db *DB) AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error) {
+	lock, err := attachLock(ctx, db, attachment.EntityType, attachment.EntityID, username)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment.Author = username
+	attachment.CreatedAt = time.Now()
+	err = db.withTx(ctx, func(tx *Tx) error {
+		// блокировка не даёт версии смениться, пока файл привязывается к ней
+		version, err := lock(ctx, tx, attachment.EntityID)
+		if err != nil {
+			return err
+		}
+		attachment.Version = version
+
+		query := squirrel.Insert("attachment").
+			Columns("id", "entity_type", "entity_id", "version", "filename", "content_type", "size", "checksum", "author", "created_at").
+			Values(attachment.ID, attachment.EntityType, attachment.EntityID, attachment.Version, attachment.Filename,
+				attachment.ContentType, attachment.Size, attachment.Checksum, attachment.Author, attachment.CreatedAt).
+			PlaceholderFormat(squirrel.Dollar)
+
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, sqlQuery, args...); err != nil {
+			return queryError(ctx, err)
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditAttach, EntityType: attachment.EntityType, EntityID: attachment.EntityID, VersionBefore: version, VersionAfter: version})
+	})
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// GetAttachments возвращает файлы сущности в порядке загрузки; version = 0 - файлы всех версий
+func (db *DB) GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error) {
+	if err := attachmentVisible(ctx, db, entityType, entityID, username); err != nil {
+		return nil, err
+	}
+
+	query := attachmentSelect().
+		Where(squirrel.Eq{"entity_type": entityType, "entity_id": entityID}).
+		OrderBy("created_at", "id")
+	if version != 0 {
+		query = query.Where(squirrel.Eq{"version": version})
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// GetAttachment возвращает сведения о файле для скачивания, если сотруднику видна сущность, к которой он приложен
+func (db *DB) GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.Attachment{}, ErrNoUser
+	}
+
+	sqlQuery, args, err := attachmentSelect().Where(squirrel.Eq{"id": attachmentID}).ToSql()
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	attachment, err := scanAttachment(db.QueryRowContext(ctx, sqlQuery, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Attachment{}, ErrNoAttachment
+		}
+		return models.Attachment{}, queryError(ctx, err)
+	}
+
+	if err = attachmentVisible(ctx, db, attachment.EntityType, attachment.EntityID, username); err != nil {
+		return models.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// attachmentVisible проверяет, что сотрудник видит сущность по правилам GetTenderBids:
+// тендер опубликован или сотрудник за него отвечает, предложение видно его автору и ответственным
+func attachmentVisible(ctx context.Context, db *DB, entityType, entityID, username string) error {
+	switch entityType {
+	case models.AuditTender:
+		return tenderBidsVisible(ctx, db, entityID, username)
+	case models.AuditBid:
+		userExist, _ := GetUser(ctx, db, username)
+		if !userExist {
+			return ErrNoUser
+		}
+		BidExist, _ := GetBid(ctx, db, entityID)
+		if !BidExist {
+			return ErrNoBid
+		}
+		if err := tenderBidsVisible(ctx, db, BidByID(ctx, db, entityID).TenderID, username); err != nil {
+			return err
+		}
+		check, _ := isUserResponsibleForBid(ctx, db, username, entityID)
+		if !check {
+			return ErrRights
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown attachment entity type %q", entityType)
+	}
+}
+
+func attachmentSelect() squirrel.SelectBuilder {
+	return squirrel.Select("id", "entity_type", "entity_id", "version", "filename", "content_type", "size", "checksum", "author", "created_at").
+		From("attachment").
+		PlaceholderFormat(squirrel.Dollar)
+}
+
+func scanAttachment(row interface{ Scan(dest ...any) error }) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.EntityType,
+		&attachment.EntityID,
+		&attachment.Version,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Checksum,
+		&attachment.Author,
+		&attachment.CreatedAt)
+	return attachment, err
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"avito.go/pkg/requestid"
+	"avito.go/pkg/uuid"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"time"
+)
+
+// recordAudit пишет событие журнала в той же транзакции, что и само изменение.
+// Организация берётся из тендера, к которому относится сущность, идентификатор запроса - из контекста.
+func recordAudit(ctx context.Context, tx runner, event models.AuditEvent) error {
+	var organization squirrel.Sqlizer
+	switch event.EntityType {
+	case models.AuditTender:
+		organization = squirrel.Expr("(SELECT organization_id FROM tender WHERE id = ?)", event.EntityID)
+	case models.AuditBid:
+		organization = squirrel.Expr("(SELECT t.organization_id FROM bid b JOIN tender t ON t.id = b.tender_id WHERE b.id = ?)", event.EntityID)
+	default:
+		return fmt.Errorf("unknown audit entity type %q", event.EntityType)
+	}
+
+	query := squirrel.Insert("audit_events").
+		Columns("id", "actor", "action", "entity_type", "entity_id", "organization_id", "version_before", "version_after", "correlation_id", "created_at").
+		Values(uuid.GenerateCorrelationID(), event.Actor, event.Action, event.EntityType, event.EntityID, organization,
+			event.VersionBefore, event.VersionAfter, requestid.FromContext(ctx), time.Now()).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return nil
+}
+
+// GetAuditEvents возвращает журнал организации от новых событий к старым; доступен только её ответственным
+func (db *DB) GetAuditEvents(ctx context.Context, filter models.AuditFilter, page models.Page, username string) ([]models.AuditEvent, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return nil, ErrNoUser
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, filter.OrganizationID)
+	if !check {
+		return nil, ErrRights
+	}
+
+	query := squirrel.Select("id", "actor", "action", "entity_type", "entity_id", "organization_id", "version_before", "version_after", "COALESCE(correlation_id, '')", "created_at").
+		From("audit_events").
+		Where(squirrel.Eq{"organization_id": filter.OrganizationID}).
+		PlaceholderFormat(squirrel.Dollar)
+	if filter.EntityType != "" {
+		query = query.Where(squirrel.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityID != "" {
+		query = query.Where(squirrel.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.Action != "" {
+		query = query.Where(squirrel.Eq{"action": filter.Action})
+	}
+	if filter.Actor != "" {
+		query = query.Where(squirrel.Eq{"actor": filter.Actor})
+	}
+	query, err := paginate(query, []keyColumn{{name: "created_at", kind: keyTime, desc: true}, {name: "id", desc: true}}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var event models.AuditEvent
+		if err = rows.Scan(
+			&event.ID,
+			&event.Actor,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityID,
+			&event.OrganizationID,
+			&event.VersionBefore,
+			&event.VersionAfter,
+			&event.CorrelationID,
+			&event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
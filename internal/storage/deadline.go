@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"time"
+)
+
+// submissionDeadlinePassed проверяет, истёк ли срок подачи предложений по тендеру к моменту now (UTC)
+func submissionDeadlinePassed(ctx context.Context, db *DB, tenderID string, now time.Time) (bool, error) {
+	query := squirrel.Select("submission_deadline").
+		From("tender").
+		Where(squirrel.Eq{"id": tenderID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var deadline sql.NullTime
+	err = db.QueryRowContext(ctx, sqlQuery, args...).Scan(&deadline)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrNoTender
+		}
+		return false, queryError(ctx, err)
+	}
+	return deadline.Valid && !now.Before(deadline.Time), nil
+}
+
+// CloseExpiredTenders закрывает опубликованные тендеры, у которых к моменту now (UTC) истёк последний из сроков.
+// Каждый тендер закрывается в своей транзакции с записью прежней версии в tender_history.
+// Возвращает идентификаторы закрытых тендеров.
+func (db *DB) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	query := squirrel.Select("id").
+		From("tender").
+		Where(squirrel.Eq{"status": "Published"}).
+		Where(squirrel.LtOrEq{"COALESCE(decision_deadline, submission_deadline)": now}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	var expired []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		expired = append(expired, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var closed []string
+	for _, tenderID := range expired {
+		var changed bool
+		err = db.withTx(ctx, func(tx *Tx) error {
+			version, err := lockTender(ctx, tx, tenderID)
+			if err != nil {
+				return err
+			}
+
+			// Пока тендер не был заблокирован, его могли закрыть вручную
+			status, err := lockedStatus(ctx, tx, "tender", tenderID)
+			if err != nil {
+				return err
+			}
+			if status != "Published" {
+				return nil
+			}
+
+			if err = archiveTender(ctx, tx, tenderID); err != nil {
+				return err
+			}
+			if err = updateTender(ctx, tx, tenderID, version, map[string]interface{}{"status": "Closed"}); err != nil {
+				return err
+			}
+			changed = true
+			return recordAudit(ctx, tx, models.AuditEvent{Actor: models.AuditActorSystem, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: tenderID, VersionBefore: version, VersionAfter: version + 1})
+		})
+		if err != nil {
+			return closed, err
+		}
+		if changed {
+			closed = append(closed, tenderID)
+		}
+	}
+	return closed, nil
+}
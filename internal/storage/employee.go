@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"strings"
+)
+
+var employeeColumns = []string{"e.id", "e.username", "COALESCE(e.first_name, '')", "COALESCE(e.last_name, '')", "e.created_at", "e.updated_at"}
+
+// GetEmployees возвращает сотрудников по алфавиту; prefix ограничивает выборку началом username
+func (db *DB) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	query := squirrel.Select(employeeColumns...).
+		From("employee e").
+		OrderBy("e.username").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if prefix != "" {
+		query = query.Where(squirrel.Like{"e.username": escapeLike(prefix) + "%"})
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	return scanUsers(rows)
+}
+
+func (db *DB) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	query := squirrel.Select(employeeColumns...).
+		From("employee e").
+		Where(squirrel.Eq{"e.username": username}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = db.QueryRowContext(ctx, sqlQuery, args...).Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, ErrNoUser
+		}
+		return models.User{}, queryError(ctx, err)
+	}
+	return user, nil
+}
+
+// GetEmployeeOrganizations возвращает организации, за которые отвечает сотрудник
+func (db *DB) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	if _, err := getUserID(ctx, db, username); err != nil {
+		return nil, err
+	}
+
+	query := squirrel.Select("o.id", "o.name", "COALESCE(o.description, '')", "COALESCE(o.type::text, '')", "o.created_at", "o.updated_at").
+		From("organization o").
+		Join(`organization_responsible "or" ON "or".organization_id = o.id`).
+		Join(`employee e ON "or".user_id = e.id`).
+		Where(squirrel.Eq{"e.username": username}).
+		OrderBy("o.name", "o.id").
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	organizations := []models.Organization{}
+	for rows.Next() {
+		var organization models.Organization
+		if err = rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.Description,
+			&organization.Type,
+			&organization.CreatedAt,
+			&organization.UpdatedAt); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+	return organizations, rows.Err()
+}
+
+func scanUsers(rows *Rows) ([]models.User, error) {
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы префикс искался буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -1,6 +1,12 @@
 package storage
 
-import "errors"
+import (
+	"avito.go/pkg/requestid"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 var ErrRights = errors.New("insufficient rights to perform the action")
 var ErrNoTender = errors.New("no such tender")
@@ -8,3 +14,57 @@ var ErrNoBid = errors.New("no such bid")
 var ErrNoVersion = errors.New("no such version")
 var ErrNoReviews = errors.New("no such review")
 var ErrNoUser = errors.New("no such user")
+var ErrDecisionMade = errors.New("decision has already been made")
+var ErrVersionMismatch = errors.New("entity version does not match")
+var ErrNoOrganization = errors.New("no such organization")
+var ErrAlreadyResponsible = errors.New("user is already responsible for the organization")
+var ErrLastResponsible = errors.New("organization must have at least one responsible")
+var ErrNotResponsible = errors.New("user is not responsible for the organization")
+var ErrDeadlinePassed = errors.New("submission deadline has passed")
+var ErrInvalidTransition = errors.New("status transition is not allowed")
+var ErrNoAttachment = errors.New("no such attachment")
+
+// DomainErrors - ожидаемые ошибки хранилища: отказ в правах, отсутствие сущности или конфликт состояния.
+// Это обычный ответ API, а не сбой; порядок важен для problem.From: проверяется первое совпадение
+var DomainErrors = []error{
+	ErrRights, ErrNoUser, ErrNoTender, ErrNoBid, ErrNoVersion, ErrNoReviews, ErrNoAttachment, ErrNoOrganization,
+	ErrDecisionMade, ErrDeadlinePassed, ErrInvalidTransition, ErrAlreadyResponsible, ErrLastResponsible,
+	ErrNotResponsible, ErrVersionMismatch,
+}
+
+// IsDomainError сообщает, является ли ошибка одной из DomainErrors
+func IsDomainError(err error) bool {
+	for _, domain := range DomainErrors {
+		if errors.Is(err, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueViolations - ошибки хранилища для нарушений уникальных индексов, которые означают гонку двух одинаковых запросов
+var uniqueViolations = map[string]error{
+	"uq_decisions_bid_created_by": ErrDecisionMade,
+	"uq_organization_responsible": ErrAlreadyResponsible,
+}
+
+// queryError оборачивает ошибку выполнения запроса к базе данных
+func queryError(ctx context.Context, err error) error {
+	var pgErr *pgconn.PgError
+	// 23505 - unique_violation
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if domainErr, ok := uniqueViolations[pgErr.ConstraintName]; ok {
+			return domainErr
+		}
+	}
+	return withRequestID(ctx, fmt.Errorf("error executing query: %w", err))
+}
+
+// withRequestID добавляет к ошибке идентификатор HTTP-запроса,
+// чтобы текст ошибки в ответе можно было сопоставить со строками логов
+func withRequestID(ctx context.Context, err error) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		return fmt.Errorf("request %s: %w", id, err)
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+// Ping проверяет, что база данных принимает соединения
+func (db *DB) Ping(ctx context.Context) error {
+	return db.DB.PingContext(ctx)
+}
+
+// WaitPing ждёт, пока база начнёт принимать соединения, проверяя её каждые interval.
+// Возвращает последнюю ошибку соединения, если ctx истёк раньше.
+func (db *DB) WaitPing(ctx context.Context, interval time.Duration) error {
+	for {
+		err := db.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database is unavailable: %w", err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SchemaVersion возвращает применённую версию схемы из goose_db_version и версию последней встроенной миграции.
+// Проба только читает таблицу: goose.GetDBVersion создаёт её при отсутствии и меняет глобальное состояние goose,
+// которым пользуются миграции. Пока таблицы нет, применённая версия - 0.
+func (db *DB) SchemaVersion(ctx context.Context) (current, latest int64, err error) {
+	err = db.QueryRowContext(ctx, "SELECT COALESCE(max(version_id), 0) FROM goose_db_version WHERE is_applied").Scan(&current)
+	var pgErr *pgconn.PgError
+	// 42P01 - undefined_table
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+		return 0, db.latest, nil
+	}
+	if err != nil {
+		return 0, db.latest, queryError(ctx, err)
+	}
+	return current, db.latest, nil
+}
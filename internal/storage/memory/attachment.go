@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) CanAttach(ctx context.Context, entityType, entityID, username string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, err := s.attachVersion(entityType, entityID, username)
+	return err
+}
+
+// attachVersion проверяет права на загрузку файла и возвращает текущую версию сущности
+func (s *Storage) attachVersion(entityType, entityID, username string) (int, error) {
+	if _, exist := s.userByName(username); !exist {
+		return 0, storage.ErrNoUser
+	}
+	switch entityType {
+	case models.AuditTender:
+		tender, ok := s.tenders[entityID]
+		if !ok {
+			return 0, storage.ErrNoTender
+		}
+		if !s.isUserResponsibleForTender(username, entityID) {
+			return 0, storage.ErrRights
+		}
+		return tender.Version, nil
+	case models.AuditBid:
+		bid, ok := s.bids[entityID]
+		if !ok {
+			return 0, storage.ErrNoBid
+		}
+		author := s.employees[bid.AuthorID]
+		if author.user.Username != username && !s.isUserResponsibleToUpdateBid(username, entityID) {
+			return 0, storage.ErrRights
+		}
+		return int(bid.Version), nil
+	default:
+		return 0, fmt.Errorf("unknown attachment entity type %q", entityType)
+	}
+}
+
+func (s *Storage) AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, err := s.attachVersion(attachment.EntityType, attachment.EntityID, username)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	attachment.Version = version
+
+	attachment.Author = username
+	attachment.CreatedAt = time.Now()
+	s.attachments = append(s.attachments, attachment)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditAttach, EntityType: attachment.EntityType, EntityID: attachment.EntityID, VersionBefore: attachment.Version, VersionAfter: attachment.Version})
+	return attachment, nil
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.attachmentVisible(entityType, entityID, username); err != nil {
+		return nil, err
+	}
+
+	attachments := []models.Attachment{}
+	for _, attachment := range s.attachments {
+		if attachment.EntityType == entityType && attachment.EntityID == entityID && (version == 0 || attachment.Version == version) {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Attachment{}, storage.ErrNoUser
+	}
+	for _, attachment := range s.attachments {
+		if attachment.ID != attachmentID {
+			continue
+		}
+		if err := s.attachmentVisible(attachment.EntityType, attachment.EntityID, username); err != nil {
+			return models.Attachment{}, err
+		}
+		return attachment, nil
+	}
+	return models.Attachment{}, storage.ErrNoAttachment
+}
+
+// attachmentVisible повторяет storage.attachmentVisible
+func (s *Storage) attachmentVisible(entityType, entityID, username string) error {
+	switch entityType {
+	case models.AuditTender:
+		return s.tenderBidsVisible(entityID, username)
+	case models.AuditBid:
+		if _, exist := s.userByName(username); !exist {
+			return storage.ErrNoUser
+		}
+		bid, ok := s.bids[entityID]
+		if !ok {
+			return storage.ErrNoBid
+		}
+		if err := s.tenderBidsVisible(bid.TenderID, username); err != nil {
+			return err
+		}
+		if !s.isUserResponsibleForBid(username, entityID) {
+			return storage.ErrRights
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown attachment entity type %q", entityType)
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/requestid"
+	"avito.go/pkg/uuid"
+	"context"
+	"sort"
+	"time"
+)
+
+// recordAudit повторяет storage.recordAudit: организация - из тендера сущности, идентификатор запроса - из контекста
+func (s *Storage) recordAudit(ctx context.Context, event models.AuditEvent) {
+	tenderID := event.EntityID
+	if event.EntityType == models.AuditBid {
+		tenderID = s.bids[event.EntityID].TenderID
+	}
+	event.ID = uuid.GenerateCorrelationID()
+	event.OrganizationID = s.tenders[tenderID].OrganizationID
+	event.CorrelationID = requestid.FromContext(ctx)
+	event.CreatedAt = time.Now()
+	s.auditEvents = append(s.auditEvents, event)
+}
+
+func (s *Storage) GetAuditEvents(ctx context.Context, filter models.AuditFilter, p models.Page, username string) ([]models.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return nil, storage.ErrNoUser
+	}
+	if !s.isUserResponsibleForOrganization(username, filter.OrganizationID) {
+		return nil, storage.ErrRights
+	}
+
+	events := []models.AuditEvent{}
+	for _, event := range s.auditEvents {
+		if matchAudit(event, filter) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return lessAudit(events[i], events[j])
+	})
+	return pageAfter(events, p, auditKey, lessAudit)
+}
+
+func matchAudit(event models.AuditEvent, filter models.AuditFilter) bool {
+	if event.OrganizationID != filter.OrganizationID {
+		return false
+	}
+	if filter.EntityType != "" && event.EntityType != filter.EntityType {
+		return false
+	}
+	if filter.EntityID != "" && event.EntityID != filter.EntityID {
+		return false
+	}
+	if filter.Action != "" && event.Action != filter.Action {
+		return false
+	}
+	if filter.Actor != "" && event.Actor != filter.Actor {
+		return false
+	}
+	return true
+}
+
+// lessAudit повторяет ORDER BY created_at DESC, id DESC
+func lessAudit(a, b models.AuditEvent) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+func auditKey(keys []string) (models.AuditEvent, error) {
+	if len(keys) != 2 {
+		return models.AuditEvent{}, cursor.ErrInvalid
+	}
+	createdAt, err := models.ParseKeyTime(keys[0])
+	if err != nil {
+		return models.AuditEvent{}, cursor.ErrInvalid
+	}
+	return models.AuditEvent{ID: keys[1], CreatedAt: createdAt}, nil
+}
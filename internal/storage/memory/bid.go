@@ -0,0 +1,288 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/uuid"
+	"context"
+	"sort"
+	"time"
+)
+
+func (s *Storage) EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Bid{}, storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidId]
+	if !ok {
+		return models.Bid{}, storage.ErrNoBid
+	}
+	if !s.isUserResponsibleToUpdateBid(username, bidId) {
+		return models.Bid{}, storage.ErrRights
+	}
+	if expectedVersion != 0 && int(bid.Version) != expectedVersion {
+		return models.Bid{}, storage.ErrVersionMismatch
+	}
+
+	if status != "" {
+		if !models.CanTransitionBid(bid.Status, status) {
+			return models.Bid{}, storage.ErrInvalidTransition
+		}
+		bid.Status = status
+	}
+	if bidName != "" {
+		bid.Name = bidName
+	}
+	if description != "" {
+		bid.Description = description
+	}
+
+	s.archiveBid(bidId)
+	bid = s.updateBid(bid)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditEdit, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version) - 1, VersionAfter: int(bid.Version)})
+	return bid, nil
+}
+
+func (s *Storage) SubmitDecisionBid(ctx context.Context, bidId string, decisionValue string, username string) (models.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Bid{}, storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidId]
+	if !ok {
+		return models.Bid{}, storage.ErrNoBid
+	}
+	if !s.isUserResponsibleForTender(username, bid.TenderID) {
+		return models.Bid{}, storage.ErrRights
+	}
+	if bid.Status == "Approved" || bid.Status == "Rejected" {
+		return models.Bid{}, storage.ErrDecisionMade
+	}
+	if !models.CanTransitionBid(bid.Status, decisionValue) || s.tenders[bid.TenderID].Status != "Published" {
+		return models.Bid{}, storage.ErrInvalidTransition
+	}
+
+	approvals := map[string]bool{}
+	for _, d := range s.decisions {
+		if d.bidID != bidId {
+			continue
+		}
+		if d.createdBy == username {
+			return models.Bid{}, storage.ErrDecisionMade
+		}
+		if d.decision == "Approved" {
+			approvals[d.createdBy] = true
+		}
+	}
+	s.decisions = append(s.decisions, decision{bidID: bidId, decision: decisionValue, createdBy: username})
+
+	// Одного отказа достаточно, чтобы отклонить предложение
+	if decisionValue == "Rejected" {
+		s.archiveBid(bidId)
+		bid.Status = "Rejected"
+		bid = s.updateBid(bid)
+		s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version) - 1, VersionAfter: int(bid.Version)})
+		return bid, nil
+	}
+
+	// Для согласования нужен кворум: min(3, количество ответственных за организацию)
+	approvals[username] = true
+	tender := s.tenders[bid.TenderID]
+	if len(approvals) < min(3, s.countResponsibles(tender.OrganizationID)) {
+		s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version), VersionAfter: int(bid.Version)})
+		return bid, nil
+	}
+
+	s.archiveBid(bidId)
+	bid.Status = "Approved"
+	bid = s.updateBid(bid)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version) - 1, VersionAfter: int(bid.Version)})
+
+	s.archiveTender(tender.ID)
+	tender.Status = "Closed"
+	tender = s.updateTender(tender)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: tender.ID, VersionBefore: tender.Version - 1, VersionAfter: tender.Version})
+	return bid, nil
+}
+
+func (s *Storage) GetTenderBids(ctx context.Context, tenderID, username string, p models.Page) ([]models.Bid, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.tenderBidsVisible(tenderID, username); err != nil {
+		return []models.Bid{}, err
+	}
+
+	var bids []models.Bid
+	for _, bid := range s.bids {
+		if bid.TenderID == tenderID && s.isUserResponsibleForBid(username, bid.ID) {
+			bids = append(bids, bid)
+		}
+	}
+	sortBids(bids)
+	bids, err := pageAfter(bids, p, bidByNameKey, lessBidByName)
+	if err != nil {
+		return nil, err
+	}
+	if len(bids) == 0 {
+		return bids, storage.ErrNoBid
+	}
+	return bids, nil
+}
+
+// tenderBidsVisible повторяет storage.tenderBidsVisible: тендер опубликован или сотрудник отвечает за его организацию
+func (s *Storage) tenderBidsVisible(tenderID, username string) error {
+	if _, exist := s.userByName(username); !exist {
+		return storage.ErrNoUser
+	}
+	if _, ok := s.tenders[tenderID]; !ok {
+		return storage.ErrNoTender
+	}
+	status, _ := s.tenderStatus(tenderID, username)
+	if status != "Published" && !s.isUserResponsibleForTender(username, tenderID) {
+		return storage.ErrRights
+	}
+	return nil
+}
+
+func (s *Storage) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reviewer, exist := s.userByName(username)
+	if !exist {
+		return models.Bid{}, storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidId]
+	if !ok {
+		return models.Bid{}, storage.ErrNoBid
+	}
+	if !s.isUserResponsibleForTender(username, bid.TenderID) {
+		return models.Bid{}, storage.ErrRights
+	}
+
+	s.reviews = append(s.reviews, review{
+		id:          uuid.GenerateCorrelationID(),
+		bidID:       bidId,
+		review:      bidFeedback,
+		reviewerID:  reviewer.ID,
+		bidAuthorID: bid.AuthorID,
+		createdAt:   time.Now(),
+	})
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditFeedback, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version), VersionAfter: int(bid.Version)})
+	return bid, nil
+}
+
+func (s *Storage) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, p models.Page) ([]models.FeedBack, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exist := s.userByName(authorUsername); !exist {
+		return nil, storage.ErrNoUser
+	}
+	requester, exist := s.userByName(requesterUsername)
+	if !exist {
+		return nil, storage.ErrNoUser
+	}
+	if _, ok := s.tenders[tenderId]; !ok {
+		return nil, storage.ErrNoTender
+	}
+	if !s.isUserResponsibleForTender(authorUsername, tenderId) {
+		return nil, storage.ErrRights
+	}
+
+	hasBid := false
+	for _, bid := range s.bids {
+		if s.isBidAuthor(requester.ID, bid) && bid.TenderID == tenderId {
+			hasBid = true
+		}
+	}
+	if !hasBid {
+		return nil, storage.ErrNoReviews
+	}
+
+	var reviews []models.FeedBack
+	for _, r := range s.reviews {
+		if bid, ok := s.bids[r.bidID]; ok && s.isBidAuthor(requester.ID, bid) {
+			reviews = append(reviews, models.FeedBack{ID: r.id, Description: r.review, CreatedAt: r.createdAt})
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		return lessFeedback(reviews[i], reviews[j])
+	})
+	return pageAfter(reviews, p, feedbackKey, lessFeedback)
+}
+
+// lessFeedback повторяет ORDER BY created_at, id
+func lessFeedback(a, b models.FeedBack) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func feedbackKey(keys []string) (models.FeedBack, error) {
+	if len(keys) != 2 {
+		return models.FeedBack{}, cursor.ErrInvalid
+	}
+	createdAt, err := models.ParseKeyTime(keys[0])
+	if err != nil {
+		return models.FeedBack{}, cursor.ErrInvalid
+	}
+	return models.FeedBack{ID: keys[1], CreatedAt: createdAt}, nil
+}
+
+func (s *Storage) GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.checkBidHistoryAccess(bidID, username); err != nil {
+		return nil, err
+	}
+
+	bids := append([]models.Bid{s.bids[bidID]}, s.bidHistory[bidID]...)
+	sort.Slice(bids, func(i, j int) bool {
+		return bids[i].Version > bids[j].Version
+	})
+	return page(bids, limit, offset), nil
+}
+
+func (s *Storage) DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.checkBidHistoryAccess(bidID, username); err != nil {
+		return models.VersionDiff{}, err
+	}
+
+	versions := append([]models.Bid{s.bids[bidID]}, s.bidHistory[bidID]...)
+	versionOf := func(b models.Bid) int { return int(b.Version) }
+	fromBid, ok := findVersion(versions, from, versionOf)
+	if !ok {
+		return models.VersionDiff{}, storage.ErrNoVersion
+	}
+	toBid, ok := findVersion(versions, to, versionOf)
+	if !ok {
+		return models.VersionDiff{}, storage.ErrNoVersion
+	}
+	return models.DiffBids(fromBid, toBid), nil
+}
+
+func (s *Storage) checkBidHistoryAccess(bidID, username string) error {
+	if _, exist := s.userByName(username); !exist {
+		return storage.ErrNoUser
+	}
+	if _, ok := s.bids[bidID]; !ok {
+		return storage.ErrNoBid
+	}
+	if !s.isUserResponsibleToUpdateBid(username, bidID) {
+		return storage.ErrRights
+	}
+	return nil
+}
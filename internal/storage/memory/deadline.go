@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (s *Storage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var closed []string
+	for id, tender := range s.tenders {
+		deadline := tender.Deadline()
+		if tender.Status != "Published" || deadline == nil || now.Before(*deadline) {
+			continue
+		}
+		s.archiveTender(id)
+		tender.Status = "Closed"
+		tender = s.updateTender(tender)
+		s.recordAudit(ctx, models.AuditEvent{Actor: models.AuditActorSystem, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: id, VersionBefore: tender.Version - 1, VersionAfter: tender.Version})
+		closed = append(closed, id)
+	}
+	sort.Strings(closed)
+	return closed, nil
+}
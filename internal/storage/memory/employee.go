@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"sort"
+	"strings"
+)
+
+func (s *Storage) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, e := range s.employees {
+		if strings.HasPrefix(e.user.Username, prefix) {
+			users = append(users, e.user)
+		}
+	}
+	sortUsers(users)
+	return page(users, limit, offset), nil
+}
+
+func (s *Storage) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exist := s.userByName(username)
+	if !exist {
+		return models.User{}, storage.ErrNoUser
+	}
+	return user, nil
+}
+
+func (s *Storage) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exist := s.userByName(username)
+	if !exist {
+		return nil, storage.ErrNoUser
+	}
+
+	organizations := []models.Organization{}
+	for _, r := range s.responsibles {
+		if r.userID == user.ID {
+			organizations = append(organizations, s.organizations[r.organizationID])
+		}
+	}
+	sortOrganizations(organizations)
+	return organizations, nil
+}
+
+func (s *Storage) GetUserCredentials(ctx context.Context, username string) (models.User, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.employees {
+		if e.user.Username == username {
+			return e.user, e.passwordHash, nil
+		}
+	}
+	return models.User{}, "", storage.ErrNoUser
+}
+
+func sortUsers(users []models.User) {
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+}
@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Storage) AddBid(ctx context.Context, bid models.Bid, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	author, exist := s.employees[userID]
+	if !exist {
+		return storage.ErrNoUser
+	}
+	if bid.AuthorType == "Organization" {
+		if !s.isResponsible(userID, bid.AuthorID) {
+			return storage.ErrRights
+		}
+	} else if bid.AuthorID != userID {
+		return storage.ErrRights
+	}
+	if s.isUserResponsibleForTender(author.user.Username, bid.TenderID) {
+		return storage.ErrRights
+	}
+	tender, ok := s.tenders[bid.TenderID]
+	if !ok {
+		return storage.ErrNoTender
+	}
+	if tender.SubmissionDeadline != nil && !time.Now().Before(*tender.SubmissionDeadline) {
+		return storage.ErrDeadlinePassed
+	}
+	if _, ok := s.bids[bid.ID]; ok {
+		return fmt.Errorf("bid %s already exists", bid.ID)
+	}
+	s.bids[bid.ID] = bid
+	s.recordAudit(ctx, models.AuditEvent{Actor: author.user.Username, Action: models.AuditCreate, EntityType: models.AuditBid, EntityID: bid.ID, VersionBefore: 0, VersionAfter: int(bid.Version)})
+	return nil
+}
+
+func (s *Storage) AddTender(ctx context.Context, tender models.Tender, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return storage.ErrNoUser
+	}
+	if !s.isUserResponsibleForOrganization(username, tender.OrganizationID) {
+		return storage.ErrRights
+	}
+	if _, ok := s.tenders[tender.ID]; ok {
+		return fmt.Errorf("tender %s already exists", tender.ID)
+	}
+	s.tenders[tender.ID] = tender
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditCreate, EntityType: models.AuditTender, EntityID: tender.ID, VersionBefore: 0, VersionAfter: tender.Version})
+	return nil
+}
+
+func (s *Storage) GetMyBids(ctx context.Context, p models.Page, username string) ([]models.Bid, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exist := s.userByName(username)
+	if !exist && username != "" {
+		return nil, storage.ErrNoUser
+	}
+
+	var bids []models.Bid
+	for _, bid := range s.bids {
+		if exist && s.isBidAuthor(user.ID, bid) {
+			bids = append(bids, bid)
+		}
+	}
+	sortBids(bids)
+	return pageAfter(bids, p, bidByNameKey, lessBidByName)
+}
+
+func (s *Storage) GetMyTenders(ctx context.Context, p models.Page, username string) ([]models.Tender, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exist := s.userByName(username)
+	if !exist && username != "" {
+		return nil, storage.ErrNoUser
+	}
+
+	var tenders []models.Tender
+	for _, tender := range s.tenders {
+		if exist && s.isResponsible(user.ID, tender.OrganizationID) {
+			tenders = append(tenders, tender)
+		}
+	}
+	sortTenders(tenders)
+	return pageAfter(tenders, p, tenderByNameKey, lessTenderByName)
+}
+
+func (s *Storage) GetBidStatus(ctx context.Context, bidID, username string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return "", storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidID]
+	if !ok {
+		return "", storage.ErrNoBid
+	}
+	if (bid.Status == "Created" || bid.Status == "Canceled") && !s.isUserResponsibleForBid(username, bidID) {
+		return "", storage.ErrRights
+	}
+	return bid.Status, nil
+}
+
+func (s *Storage) GetTenderStatus(ctx context.Context, tenderID, username string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tenderStatus(tenderID, username)
+}
+
+// tenderStatus - GetTenderStatus без блокировки, для вызова из других методов
+func (s *Storage) tenderStatus(tenderID, username string) (string, error) {
+	if _, exist := s.userByName(username); !exist {
+		return "", storage.ErrNoUser
+	}
+	tender, ok := s.tenders[tenderID]
+	if !ok {
+		return "", storage.ErrNoTender
+	}
+	if (tender.Status == "Created" || tender.Status == "Closed") && !s.isUserResponsibleForTender(username, tenderID) {
+		return "", storage.ErrRights
+	}
+	return tender.Status, nil
+}
+
+func (s *Storage) UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Bid{}, storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidID]
+	if !ok {
+		return models.Bid{}, storage.ErrNoBid
+	}
+	if !s.isUserResponsibleToUpdateBid(username, bidID) {
+		return models.Bid{}, storage.ErrRights
+	}
+	if !models.CanTransitionBid(bid.Status, status) {
+		return models.Bid{}, storage.ErrInvalidTransition
+	}
+	s.archiveBid(bidID)
+	bid.Status = status
+	bid = s.updateBid(bid)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditBid, EntityID: bidID, VersionBefore: int(bid.Version) - 1, VersionAfter: int(bid.Version)})
+	return bid, nil
+}
+
+func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderID, status, username string) (models.Tender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Tender{}, storage.ErrNoUser
+	}
+	tender, ok := s.tenders[tenderID]
+	if !ok {
+		return models.Tender{}, storage.ErrNoTender
+	}
+	if !s.isUserResponsibleForTender(username, tenderID) {
+		return models.Tender{}, storage.ErrRights
+	}
+	if !models.CanTransitionTender(tender.Status, status) {
+		return models.Tender{}, storage.ErrInvalidTransition
+	}
+	s.archiveTender(tenderID)
+	tender.Status = status
+	tender = s.updateTender(tender)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: tenderID, VersionBefore: tender.Version - 1, VersionAfter: tender.Version})
+	return tender, nil
+}
+
+func (s *Storage) RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Bid{}, storage.ErrNoUser
+	}
+	bid, ok := s.bids[bidID]
+	if !ok {
+		return models.Bid{}, storage.ErrNoBid
+	}
+	snapshot, ok := findVersion(s.bidHistory[bidID], version, func(b models.Bid) int { return int(b.Version) })
+	if !ok {
+		return models.Bid{}, storage.ErrNoVersion
+	}
+	if !s.isUserResponsibleToUpdateBid(username, bidID) {
+		return models.Bid{}, storage.ErrRights
+	}
+	if snapshot.Status != bid.Status && !models.CanTransitionBid(bid.Status, snapshot.Status) {
+		return models.Bid{}, storage.ErrInvalidTransition
+	}
+	s.archiveBid(bidID)
+	bid.Name = snapshot.Name
+	bid.Description = snapshot.Description
+	bid.Status = snapshot.Status
+	bid = s.updateBid(bid)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditRollback, EntityType: models.AuditBid, EntityID: bidID, VersionBefore: int(bid.Version) - 1, VersionAfter: int(bid.Version)})
+	return bid, nil
+}
+
+func (s *Storage) RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Tender{}, storage.ErrNoUser
+	}
+	tender, ok := s.tenders[tenderID]
+	if !ok {
+		return models.Tender{}, storage.ErrNoTender
+	}
+	snapshot, ok := findVersion(s.tenderHistory[tenderID], version, func(t models.Tender) int { return t.Version })
+	if !ok {
+		return models.Tender{}, storage.ErrNoVersion
+	}
+	if !s.isUserResponsibleForTender(username, tenderID) {
+		return models.Tender{}, storage.ErrRights
+	}
+	if snapshot.Status != tender.Status && !models.CanTransitionTender(tender.Status, snapshot.Status) {
+		return models.Tender{}, storage.ErrInvalidTransition
+	}
+	s.archiveTender(tenderID)
+	tender.Name = snapshot.Name
+	tender.Description = snapshot.Description
+	tender.ServiceType = snapshot.ServiceType
+	tender.Status = snapshot.Status
+	tender.SubmissionDeadline = snapshot.SubmissionDeadline
+	tender.DecisionDeadline = snapshot.DecisionDeadline
+	tender = s.updateTender(tender)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditRollback, EntityType: models.AuditTender, EntityID: tenderID, VersionBefore: tender.Version - 1, VersionAfter: tender.Version})
+	return tender, nil
+}
+
+func findVersion[T any](history []T, version int, versionOf func(T) int) (T, bool) {
+	for _, snapshot := range history {
+		if versionOf(snapshot) == version {
+			return snapshot, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,12 @@
+package memory
+
+import "context"
+
+// Ping и SchemaVersion: хранилищу в памяти не нужны ни соединение, ни миграции, оно всегда готово
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *Storage) SchemaVersion(ctx context.Context) (current, latest int64, err error) {
+	return 0, 0, nil
+}
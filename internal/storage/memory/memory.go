@@ -0,0 +1,241 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"sort"
+	"sync"
+	"time"
+)
+
+type employee struct {
+	user         models.User
+	passwordHash string
+}
+
+type responsible struct {
+	organizationID string
+	userID         string
+}
+
+type decision struct {
+	bidID     string
+	decision  string
+	createdBy string
+}
+
+type review struct {
+	id          string
+	bidID       string
+	review      string
+	reviewerID  string
+	bidAuthorID string
+	createdAt   time.Time
+}
+
+// Storage хранит все данные в памяти процесса и повторяет правила прав, версионирования и ошибки storage.DB.
+// Подходит для локального запуска и тестов без Postgres; данные теряются при перезапуске.
+type Storage struct {
+	mu sync.RWMutex
+
+	employees     map[string]employee
+	organizations map[string]models.Organization
+	responsibles  []responsible
+	tenders       map[string]models.Tender
+	tenderHistory map[string][]models.Tender
+	bids          map[string]models.Bid
+	bidHistory    map[string][]models.Bid
+	decisions     []decision
+	reviews       []review
+	auditEvents   []models.AuditEvent
+	attachments   []models.Attachment
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+func New() *Storage {
+	return &Storage{
+		employees:     map[string]employee{},
+		organizations: map[string]models.Organization{},
+		tenders:       map[string]models.Tender{},
+		tenderHistory: map[string][]models.Tender{},
+		bids:          map[string]models.Bid{},
+		bidHistory:    map[string][]models.Bid{},
+	}
+}
+
+func (s *Storage) Close() error {
+	return nil
+}
+
+func (s *Storage) userByName(username string) (models.User, bool) {
+	for _, e := range s.employees {
+		if e.user.Username == username {
+			return e.user, true
+		}
+	}
+	return models.User{}, false
+}
+
+func (s *Storage) isResponsible(userID, organizationID string) bool {
+	for _, r := range s.responsibles {
+		if r.userID == userID && r.organizationID == organizationID {
+			return true
+		}
+	}
+	return false
+}
+
+// isBidAuthor - предложение подал сам сотрудник или организация, за которую он отвечает
+func (s *Storage) isBidAuthor(userID string, bid models.Bid) bool {
+	if bid.AuthorType == "Organization" {
+		return s.isResponsible(userID, bid.AuthorID)
+	}
+	return bid.AuthorID == userID
+}
+
+func (s *Storage) countResponsibles(organizationID string) int {
+	count := 0
+	for _, r := range s.responsibles {
+		if r.organizationID == organizationID {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Storage) isUserResponsibleForOrganization(username, organizationID string) bool {
+	user, ok := s.userByName(username)
+	return ok && s.isResponsible(user.ID, organizationID)
+}
+
+func (s *Storage) isUserResponsibleForTender(username, tenderID string) bool {
+	tender, ok := s.tenders[tenderID]
+	return ok && s.isUserResponsibleForOrganization(username, tender.OrganizationID)
+}
+
+// isUserResponsibleToUpdateBid - пользователь отвечает за организацию-автора предложения
+// или за ту же организацию, что и сотрудник-автор
+func (s *Storage) isUserResponsibleToUpdateBid(username, bidID string) bool {
+	bid, ok := s.bids[bidID]
+	if !ok {
+		return false
+	}
+	user, ok := s.userByName(username)
+	if !ok {
+		return false
+	}
+	if bid.AuthorType == "Organization" {
+		return s.isResponsible(user.ID, bid.AuthorID)
+	}
+	for _, r := range s.responsibles {
+		if r.userID == bid.AuthorID && s.isResponsible(user.ID, r.organizationID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Storage) isUserResponsibleForBid(username, bidID string) bool {
+	bid, ok := s.bids[bidID]
+	if !ok {
+		return false
+	}
+	if author, ok := s.employees[bid.AuthorID]; ok && author.user.Username == username {
+		return true
+	}
+	return s.isUserResponsibleToUpdateBid(username, bidID) || s.isUserResponsibleForTender(username, bid.TenderID)
+}
+
+// archiveTender и updateTender повторяют пару archiveTender/updateTender из storage: снимок в историю, затем версия +1
+func (s *Storage) archiveTender(tenderID string) {
+	s.tenderHistory[tenderID] = append(s.tenderHistory[tenderID], s.tenders[tenderID])
+}
+
+func (s *Storage) updateTender(tender models.Tender) models.Tender {
+	tender.Version++
+	tender.UpdatedAt = time.Now()
+	s.tenders[tender.ID] = tender
+	return tender
+}
+
+func (s *Storage) archiveBid(bidID string) {
+	s.bidHistory[bidID] = append(s.bidHistory[bidID], s.bids[bidID])
+}
+
+func (s *Storage) updateBid(bid models.Bid) models.Bid {
+	bid.Version++
+	bid.UpdatedAt = time.Now()
+	s.bids[bid.ID] = bid
+	return bid
+}
+
+// page применяет limit/offset к уже отсортированной выборке
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// pageAfter - страница выборки, отсортированной по less.
+// С курсором страница начинается сразу за записью, восстановленной из ключа функцией probe.
+func pageAfter[T any](items []T, p models.Page, probe func(keys []string) (T, error), less func(a, b T) bool) ([]T, error) {
+	if p.After == nil {
+		return page(items, p.Limit, p.Offset), nil
+	}
+	after, err := probe(p.After)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(items), func(i int) bool {
+		return less(after, items[i])
+	})
+	return page(items[i:], p.Limit, 0), nil
+}
+
+// sortTenders и sortBids повторяют ORDER BY name, id
+func sortTenders(tenders []models.Tender) {
+	sort.Slice(tenders, func(i, j int) bool {
+		return lessTenderByName(tenders[i], tenders[j])
+	})
+}
+
+func sortBids(bids []models.Bid) {
+	sort.Slice(bids, func(i, j int) bool {
+		return lessBidByName(bids[i], bids[j])
+	})
+}
+
+func lessTenderByName(a, b models.Tender) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.ID < b.ID
+}
+
+func lessBidByName(a, b models.Bid) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.ID < b.ID
+}
+
+func tenderByNameKey(keys []string) (models.Tender, error) {
+	if len(keys) != 2 {
+		return models.Tender{}, cursor.ErrInvalid
+	}
+	return models.Tender{Name: keys[0], ID: keys[1]}, nil
+}
+
+func bidByNameKey(keys []string) (models.Bid, error) {
+	if len(keys) != 2 {
+		return models.Bid{}, cursor.ErrInvalid
+	}
+	return models.Bid{Name: keys[0], ID: keys[1]}, nil
+}
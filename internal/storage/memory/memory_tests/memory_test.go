@@ -0,0 +1,486 @@
+package memory_test
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/internal/storage/memory"
+	"avito.go/pkg/cursor"
+	"avito.go/pkg/requestid"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStorage - организация с тремя ответственными, сторонний автор предложения и один опубликованный тендер
+func newStorage(t *testing.T) *memory.Storage {
+	s := memory.New()
+	err := s.Load(memory.Seed{
+		Employees: []memory.SeedEmployee{
+			{User: models.User{ID: "u1", Username: "alice"}, Password: "secret"},
+			{User: models.User{ID: "u2", Username: "bob"}},
+			{User: models.User{ID: "u3", Username: "carol"}},
+			{User: models.User{ID: "u4", Username: "dave"}},
+		},
+		Organizations: []models.Organization{
+			{ID: "o1", Name: "Org"},
+			{ID: "o2", Name: "Vendor"},
+		},
+		Responsibles: []memory.SeedResponsible{
+			{OrganizationID: "o1", Username: "alice"},
+			{OrganizationID: "o1", Username: "bob"},
+			{OrganizationID: "o1", Username: "carol"},
+			{OrganizationID: "o2", Username: "dave"},
+		},
+		Tenders: []models.Tender{
+			{ID: "t1", Name: "Tender", ServiceType: "Delivery", Status: "Published", OrganizationID: "o1", Version: 1},
+		},
+		Bids: []models.Bid{
+			{ID: "b1", Name: "Bid", Status: "Published", TenderID: "t1", AuthorType: "User", AuthorID: "u4", Version: 1},
+		},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestEditTender_RightsAndVersions(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	_, err := s.EditTender(ctx, "t1", "nobody", "New", "", "", "", 0)
+	assert.ErrorIs(t, err, storage.ErrNoUser)
+
+	_, err = s.EditTender(ctx, "t1", "dave", "New", "", "", "", 0)
+	assert.ErrorIs(t, err, storage.ErrRights)
+
+	_, err = s.EditTender(ctx, "t2", "alice", "New", "", "", "", 0)
+	assert.ErrorIs(t, err, storage.ErrNoTender)
+
+	_, err = s.EditTender(ctx, "t1", "alice", "New", "", "", "", 5)
+	assert.ErrorIs(t, err, storage.ErrVersionMismatch)
+
+	tender, err := s.EditTender(ctx, "t1", "alice", "New", "", "", "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tender.Version)
+	assert.Equal(t, "New", tender.Name)
+
+	rolledBack, err := s.RollbackTender(ctx, "t1", 1, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Tender", rolledBack.Name)
+	assert.Equal(t, 3, rolledBack.Version)
+
+	_, err = s.RollbackTender(ctx, "t1", 7, "alice")
+	assert.ErrorIs(t, err, storage.ErrNoVersion)
+
+	versions, err := s.GetTenderVersions(ctx, "t1", "alice", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 2, 1}, []int{versions[0].Version, versions[1].Version, versions[2].Version})
+
+	diff, err := s.DiffTenderVersions(ctx, "t1", "alice", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Field: "name", From: "Tender", To: "New"}}, diff.Changes)
+}
+
+func TestSubmitDecisionBid_Quorum(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	_, err := s.SubmitDecisionBid(ctx, "b1", "Approved", "dave")
+	assert.ErrorIs(t, err, storage.ErrRights)
+
+	bid, err := s.SubmitDecisionBid(ctx, "b1", "Approved", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Published", bid.Status)
+
+	_, err = s.SubmitDecisionBid(ctx, "b1", "Approved", "alice")
+	assert.ErrorIs(t, err, storage.ErrDecisionMade)
+
+	_, err = s.SubmitDecisionBid(ctx, "b1", "Approved", "bob")
+	require.NoError(t, err)
+
+	bid, err = s.SubmitDecisionBid(ctx, "b1", "Approved", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "Approved", bid.Status)
+
+	status, err := s.GetTenderStatus(ctx, "t1", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", status)
+}
+
+func TestSubmitDecisionBid_SingleRejection(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	bid, err := s.SubmitDecisionBid(ctx, "b1", "Rejected", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "Rejected", bid.Status)
+
+	_, err = s.SubmitDecisionBid(ctx, "b1", "Approved", "alice")
+	assert.ErrorIs(t, err, storage.ErrDecisionMade)
+}
+
+func TestRemoveOrganizationResponsible_KeepsLast(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	err := s.RemoveOrganizationResponsible(ctx, "o2", "dave", "dave")
+	assert.ErrorIs(t, err, storage.ErrLastResponsible)
+
+	err = s.RemoveOrganizationResponsible(ctx, "o1", "alice", "dave")
+	assert.ErrorIs(t, err, storage.ErrNotResponsible)
+
+	err = s.RemoveOrganizationResponsible(ctx, "o1", "alice", "nobody")
+	assert.ErrorIs(t, err, storage.ErrNotResponsible)
+
+	err = s.RemoveOrganizationResponsible(ctx, "o1", "alice", "bob")
+	require.NoError(t, err)
+
+	responsibles, err := s.GetOrganizationResponsibles(ctx, "o1")
+	require.NoError(t, err)
+	assert.Len(t, responsibles, 2)
+}
+
+func TestGetUserCredentials_HashesSeedPassword(t *testing.T) {
+	s := newStorage(t)
+
+	user, hash, err := s.GetUserCredentials(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", user.ID)
+	assert.NotEqual(t, "secret", hash)
+
+	_, _, err = s.GetUserCredentials(context.Background(), "nobody")
+	assert.ErrorIs(t, err, storage.ErrNoUser)
+}
+
+func TestGetTenders_Search(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	for _, tender := range []models.Tender{
+		{ID: "t2", Name: "Road repair", Description: "Repair of the main road", ServiceType: "Construction", Status: "Published", OrganizationID: "o1", Version: 1},
+		{ID: "t3", Name: "Office cleaning", Description: "Cleaning after road works", ServiceType: "Delivery", Status: "Published", OrganizationID: "o1", Version: 1},
+		{ID: "t4", Name: "Road survey", Description: "Draft", ServiceType: "Construction", Status: "Created", OrganizationID: "o1", Version: 1},
+		{ID: "t5", Name: "Bridge", Description: `<img src=x onerror="alert(1)"> bridge`, ServiceType: "Construction", Status: "Published", OrganizationID: "o1", Version: 1},
+	} {
+		require.NoError(t, s.AddTender(ctx, tender, "alice"))
+	}
+
+	tenders, err := s.GetTenders(ctx, models.TenderFilter{Limit: 10, Query: "ROAD"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 2)
+	assert.Equal(t, "t2", tenders[0].ID)
+	assert.Greater(t, tenders[0].Rank, tenders[1].Rank)
+	assert.Equal(t, "Cleaning after <b>road</b> works", tenders[1].Snippet)
+
+	tenders, err = s.GetTenders(ctx, models.TenderFilter{Limit: 10, Query: "road cleaning"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "t3", tenders[0].ID)
+
+	// Разметка из описания экранируется, в сниппете остаётся только подсветка
+	tenders, err = s.GetTenders(ctx, models.TenderFilter{Limit: 10, Query: "bridge"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <b>bridge</b>", tenders[0].Snippet)
+}
+
+func TestGetTenders_FilterVisibilityAndSort(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddTender(ctx, models.Tender{ID: "t2", Name: "Alpha", ServiceType: "Construction", Status: "Created", OrganizationID: "o1", Version: 1}, "alice"))
+	require.NoError(t, s.AddTender(ctx, models.Tender{ID: "t3", Name: "Beta", ServiceType: "Delivery", Status: "Published", OrganizationID: "o2", Version: 1}, "dave"))
+
+	// Черновик чужой организации не виден ни анониму, ни постороннему сотруднику
+	for _, username := range []string{"", "dave"} {
+		tenders, err := s.GetTenders(ctx, models.TenderFilter{Limit: 10, Status: []string{"Created", "Published"}, Username: username})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(tenders))
+		for _, tender := range tenders {
+			ids = append(ids, tender.ID)
+		}
+		assert.NotContains(t, ids, "t2", username)
+	}
+
+	tenders, err := s.GetTenders(ctx, models.TenderFilter{Limit: 10, Status: []string{"Created"}, Username: "bob"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "t2", tenders[0].ID)
+
+	tenders, err = s.GetTenders(ctx, models.TenderFilter{Limit: 10, Sort: models.TenderSortName, Desc: true})
+	require.NoError(t, err)
+	require.Len(t, tenders, 2)
+	assert.Equal(t, "Tender", tenders[0].Name)
+	assert.Equal(t, "Beta", tenders[1].Name)
+
+	tenders, err = s.GetTenders(ctx, models.TenderFilter{Limit: 10, OrganizationID: "o2"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "t3", tenders[0].ID)
+}
+
+func TestGetTenders_CursorPages(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	for _, tender := range []models.Tender{
+		{ID: "t2", Name: "Alpha", ServiceType: "Delivery", Status: "Published", OrganizationID: "o1", Version: 1},
+		{ID: "t3", Name: "Alpha", ServiceType: "Delivery", Status: "Published", OrganizationID: "o1", Version: 1},
+		{ID: "t4", Name: "Beta", ServiceType: "Delivery", Status: "Published", OrganizationID: "o1", Version: 1},
+	} {
+		require.NoError(t, s.AddTender(ctx, tender, "alice"))
+	}
+
+	filter := models.TenderFilter{Limit: 2}
+	var ids []string
+	for {
+		tenders, err := s.GetTenders(ctx, filter)
+		require.NoError(t, err)
+		for _, tender := range tenders {
+			ids = append(ids, tender.ID)
+		}
+		if len(tenders) < filter.Limit {
+			break
+		}
+		filter.After = filter.Key(tenders[len(tenders)-1])
+	}
+	assert.Equal(t, []string{"t2", "t3", "t4", "t1"}, ids)
+
+	_, err := s.GetTenders(ctx, models.TenderFilter{Limit: 2, Sort: models.TenderSortCreatedAt, After: []string{"yesterday", "t1"}})
+	assert.ErrorIs(t, err, cursor.ErrInvalid)
+}
+
+func TestAddBid_AuthorTypes(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	// Сотрудник подаёт предложение пользователя только от своего имени
+	err := s.AddBid(ctx, models.Bid{ID: "b2", Name: "Bid", Status: "Created", TenderID: "t1", AuthorType: "User", AuthorID: "u1", Version: 1}, "u4")
+	assert.ErrorIs(t, err, storage.ErrRights)
+
+	// Предложение организации - только ответственный за неё
+	err = s.AddBid(ctx, models.Bid{ID: "b2", Name: "Bid", Status: "Created", TenderID: "t1", AuthorType: "Organization", AuthorID: "o1", Version: 1}, "u4")
+	assert.ErrorIs(t, err, storage.ErrRights)
+
+	require.NoError(t, s.AddBid(ctx, models.Bid{ID: "b2", Name: "Bid", Status: "Created", TenderID: "t1", AuthorType: "Organization", AuthorID: "o2", Version: 1}, "u4"))
+
+	bid, err := s.EditBid(ctx, "b2", "dave", "Edited", "", "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "o2", bid.AuthorID)
+	assert.Equal(t, "Organization", bid.AuthorType)
+
+	_, err = s.EditBid(ctx, "b2", "bob", "Stolen", "", "", 0)
+	assert.ErrorIs(t, err, storage.ErrRights)
+}
+
+func TestDeadlines_RejectBidsAndCloseTenders(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+	require.NoError(t, s.AddTender(ctx, models.Tender{ID: "t2", Name: "Expired", Status: "Published", OrganizationID: "o1", Version: 1, SubmissionDeadline: &past}, "alice"))
+	require.NoError(t, s.AddTender(ctx, models.Tender{ID: "t3", Name: "Deciding", Status: "Published", OrganizationID: "o1", Version: 1, SubmissionDeadline: &past, DecisionDeadline: &future}, "alice"))
+
+	err := s.AddBid(ctx, models.Bid{ID: "b2", Name: "Late", Status: "Created", TenderID: "t2", AuthorType: "User", AuthorID: "u4", Version: 1}, "u4")
+	assert.ErrorIs(t, err, storage.ErrDeadlinePassed)
+
+	closed, err := s.CloseExpiredTenders(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2"}, closed)
+
+	status, err := s.GetTenderStatus(ctx, "t2", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", status)
+	versions, err := s.GetTenderVersions(ctx, "t2", "alice", 5, 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "Published", versions[1].Status)
+
+	// Повторный проход не трогает уже закрытые тендеры
+	closed, err = s.CloseExpiredTenders(ctx, future.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t3"}, closed)
+}
+
+func TestUpdateStatus_Transitions(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	_, err := s.UpdateTenderStatus(ctx, "t1", "Created", "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	tender, err := s.UpdateTenderStatus(ctx, "t1", "Closed", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", tender.Status)
+
+	_, err = s.UpdateTenderStatus(ctx, "t1", "Published", "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	bid, err := s.UpdateBidStatus(ctx, "b1", "Canceled", "dave")
+	require.NoError(t, err)
+	assert.Equal(t, 2, int(bid.Version))
+
+	_, err = s.UpdateBidStatus(ctx, "b1", "Published", "dave")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+	_, err = s.EditBid(ctx, "b1", "dave", "", "", "Created", 0)
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+}
+
+func TestRollback_RespectsTransitions(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	// Закрытый тендер нельзя вернуть в Published откатом к старой версии
+	_, err := s.UpdateTenderStatus(ctx, "t1", "Closed", "alice")
+	require.NoError(t, err)
+	_, err = s.RollbackTender(ctx, "t1", 1, "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	// Отмена предложения тоже необратима
+	_, err = s.UpdateBidStatus(ctx, "b1", "Canceled", "dave")
+	require.NoError(t, err)
+	_, err = s.RollbackBid(ctx, "b1", 1, "dave")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	// Откат без смены статуса разрешён
+	edited, err := s.EditBid(ctx, "b1", "dave", "Renamed", "", "", 0)
+	require.NoError(t, err)
+	bid, err := s.RollbackBid(ctx, "b1", int(edited.Version)-1, "dave")
+	require.NoError(t, err)
+	assert.Equal(t, "Bid", bid.Name)
+	assert.Equal(t, "Canceled", bid.Status)
+}
+
+func TestSubmitDecisionBid_RequiresPublished(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddBid(ctx, models.Bid{ID: "b2", Name: "Draft", Status: "Created", TenderID: "t1", AuthorType: "User", AuthorID: "u4", Version: 1}, "u4"))
+	_, err := s.SubmitDecisionBid(ctx, "b2", "Approved", "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	_, err = s.UpdateBidStatus(ctx, "b2", "Canceled", "dave")
+	require.NoError(t, err)
+	_, err = s.SubmitDecisionBid(ctx, "b2", "Rejected", "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+
+	// По опубликованному предложению закрытого тендера решение тоже не принимается
+	_, err = s.UpdateTenderStatus(ctx, "t1", "Closed", "alice")
+	require.NoError(t, err)
+	_, err = s.SubmitDecisionBid(ctx, "b1", "Approved", "alice")
+	assert.ErrorIs(t, err, storage.ErrInvalidTransition)
+}
+
+func TestAuditEvents_RecordedAndRestricted(t *testing.T) {
+	s := newStorage(t)
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	_, err := s.EditTender(ctx, "t1", "alice", "Renamed", "", "", "", 1)
+	require.NoError(t, err)
+	_, err = s.EditBid(ctx, "b1", "dave", "Better bid", "", "", 1)
+	require.NoError(t, err)
+	_, err = s.AddFeedbackBid(ctx, "b1", "ok", "alice")
+	require.NoError(t, err)
+
+	events, err := s.GetAuditEvents(ctx, models.AuditFilter{OrganizationID: "o1"}, models.Page{Limit: 10}, "bob")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for _, event := range events {
+		assert.Equal(t, "req-1", event.CorrelationID)
+		assert.Equal(t, "o1", event.OrganizationID)
+	}
+
+	events, err = s.GetAuditEvents(ctx, models.AuditFilter{OrganizationID: "o1", EntityType: models.AuditBid, Action: models.AuditEdit}, models.Page{Limit: 10}, "bob")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "dave", events[0].Actor)
+	assert.Equal(t, "b1", events[0].EntityID)
+	assert.Equal(t, 1, events[0].VersionBefore)
+	assert.Equal(t, 2, events[0].VersionAfter)
+
+	events, err = s.GetAuditEvents(ctx, models.AuditFilter{OrganizationID: "o1", Action: models.AuditFeedback}, models.Page{Limit: 10}, "alice")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, events[0].VersionBefore, events[0].VersionAfter)
+
+	// Автор предложения не отвечает за организацию тендера и журнал не видит
+	_, err = s.GetAuditEvents(ctx, models.AuditFilter{OrganizationID: "o1"}, models.Page{Limit: 10}, "dave")
+	assert.ErrorIs(t, err, storage.ErrRights)
+}
+
+func TestAttachments_VersionsAndVisibility(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	first, err := s.AddAttachment(ctx, models.Attachment{ID: "a1", EntityType: models.AuditTender, EntityID: "t1", Filename: "spec.pdf"}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, "alice", first.Author)
+
+	_, err = s.EditTender(ctx, "t1", "alice", "Renamed", "", "", "", 1)
+	require.NoError(t, err)
+	second, err := s.AddAttachment(ctx, models.Attachment{ID: "a2", EntityType: models.AuditTender, EntityID: "t1", Filename: "spec-v2.pdf"}, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Version)
+
+	_, err = s.AddAttachment(ctx, models.Attachment{ID: "a3", EntityType: models.AuditBid, EntityID: "b1", Filename: "prices.xlsx"}, "dave")
+	require.NoError(t, err)
+
+	// Прикладывать файлы может только сторона тендера или предложения
+	_, err = s.AddAttachment(ctx, models.Attachment{ID: "a4", EntityType: models.AuditTender, EntityID: "t1"}, "dave")
+	assert.ErrorIs(t, err, storage.ErrRights)
+	_, err = s.AddAttachment(ctx, models.Attachment{ID: "a4", EntityType: models.AuditBid, EntityID: "b1"}, "alice")
+	assert.ErrorIs(t, err, storage.ErrRights)
+	// Те же права проверяются до загрузки содержимого
+	assert.NoError(t, s.CanAttach(ctx, models.AuditBid, "b1", "dave"))
+	assert.ErrorIs(t, s.CanAttach(ctx, models.AuditTender, "t1", "dave"), storage.ErrRights)
+	assert.ErrorIs(t, s.CanAttach(ctx, models.AuditBid, "b9", "dave"), storage.ErrNoBid)
+
+	attachments, err := s.GetAttachments(ctx, models.AuditTender, "t1", 0, "dave")
+	require.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	attachments, err = s.GetAttachments(ctx, models.AuditTender, "t1", 2, "dave")
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "a2", attachments[0].ID)
+
+	attachment, err := s.GetAttachment(ctx, "a3", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "prices.xlsx", attachment.Filename)
+	_, err = s.GetAttachment(ctx, "missing", "alice")
+	assert.ErrorIs(t, err, storage.ErrNoAttachment)
+
+	// После закрытия тендера файлы, как и предложения, видны только его ответственным
+	_, err = s.UpdateTenderStatus(ctx, "t1", "Closed", "alice")
+	require.NoError(t, err)
+	_, err = s.GetTenderBids(ctx, "t1", "dave", models.Page{Limit: 10})
+	assert.ErrorIs(t, err, storage.ErrRights)
+	_, err = s.GetAttachment(ctx, "a3", "dave")
+	assert.ErrorIs(t, err, storage.ErrRights)
+	_, err = s.GetAttachment(ctx, "a3", "carol")
+	assert.NoError(t, err)
+}
+
+func TestOrganizationBids_MyBidsAndReviews(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	// Предложение организации Vendor видят в своих все её ответственные, а не только подавший его сотрудник
+	require.NoError(t, s.AddBid(ctx, models.Bid{ID: "b2", Name: "Vendor bid", Status: "Published", TenderID: "t1", AuthorType: "Organization", AuthorID: "o2", Version: 1}, "u4"))
+	bids, err := s.GetMyBids(ctx, models.Page{Limit: 10}, "dave")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b1", "b2"}, []string{bids[0].ID, bids[1].ID})
+	bids, err = s.GetMyBids(ctx, models.Page{Limit: 10}, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, bids)
+
+	_, err = s.AddFeedbackBid(ctx, "b2", "good offer", "alice")
+	require.NoError(t, err)
+	reviews, err := s.GetFeedback(ctx, "t1", "alice", "dave", models.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "good offer", reviews[0].Description)
+}
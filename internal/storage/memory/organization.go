@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (s *Storage) CreateOrganization(ctx context.Context, organization models.Organization, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exist := s.userByName(username)
+	if !exist {
+		return storage.ErrNoUser
+	}
+	if _, ok := s.organizations[organization.ID]; ok {
+		return fmt.Errorf("organization %s already exists", organization.ID)
+	}
+
+	s.organizations[organization.ID] = organization
+	s.responsibles = append(s.responsibles, responsible{organizationID: organization.ID, userID: user.ID})
+	return nil
+}
+
+func (s *Storage) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for _, organization := range s.organizations {
+		organizations = append(organizations, organization)
+	}
+	sortOrganizations(organizations)
+	return page(organizations, limit, offset), nil
+}
+
+func (s *Storage) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organization, ok := s.organizations[organizationID]
+	if !ok {
+		return models.Organization{}, storage.ErrNoOrganization
+	}
+	return organization, nil
+}
+
+func (s *Storage) EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOrganizationAccess(organizationID, username); err != nil {
+		return models.Organization{}, err
+	}
+
+	organization := s.organizations[organizationID]
+	if name != "" {
+		organization.Name = name
+	}
+	if description != "" {
+		organization.Description = description
+	}
+	if organizationType != "" {
+		organization.Type = organizationType
+	}
+	organization.UpdatedAt = time.Now()
+	s.organizations[organizationID] = organization
+	return organization, nil
+}
+
+// DeleteOrganization удаляет организацию вместе с тендерами и предложениями по ним, как ON DELETE CASCADE в схеме
+func (s *Storage) DeleteOrganization(ctx context.Context, organizationID, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOrganizationAccess(organizationID, username); err != nil {
+		return err
+	}
+
+	delete(s.organizations, organizationID)
+	s.responsibles = filter(s.responsibles, func(r responsible) bool { return r.organizationID != organizationID })
+
+	for tenderID, tender := range s.tenders {
+		if tender.OrganizationID != organizationID {
+			continue
+		}
+		for bidID, bid := range s.bids {
+			if bid.TenderID != tenderID {
+				continue
+			}
+			delete(s.bids, bidID)
+			delete(s.bidHistory, bidID)
+			s.decisions = filter(s.decisions, func(d decision) bool { return d.bidID != bidID })
+			s.reviews = filter(s.reviews, func(r review) bool { return r.bidID != bidID })
+		}
+		delete(s.tenders, tenderID)
+		delete(s.tenderHistory, tenderID)
+	}
+	return nil
+}
+
+func (s *Storage) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.organizations[organizationID]; !ok {
+		return nil, storage.ErrNoOrganization
+	}
+
+	users := []models.User{}
+	for _, r := range s.responsibles {
+		if r.organizationID == organizationID {
+			users = append(users, s.employees[r.userID].user)
+		}
+	}
+	sortUsers(users)
+	return users, nil
+}
+
+func (s *Storage) AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOrganizationAccess(organizationID, username); err != nil {
+		return err
+	}
+	employee, exist := s.userByName(employeeUsername)
+	if !exist {
+		return storage.ErrNoUser
+	}
+	if s.isResponsible(employee.ID, organizationID) {
+		return storage.ErrAlreadyResponsible
+	}
+
+	s.responsibles = append(s.responsibles, responsible{organizationID: organizationID, userID: employee.ID})
+	return nil
+}
+
+func (s *Storage) RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOrganizationAccess(organizationID, username); err != nil {
+		return err
+	}
+	employee, exist := s.userByName(employeeUsername)
+	if !exist || !s.isResponsible(employee.ID, organizationID) {
+		return storage.ErrNotResponsible
+	}
+	if s.countResponsibles(organizationID) <= 1 {
+		return storage.ErrLastResponsible
+	}
+
+	s.responsibles = filter(s.responsibles, func(r responsible) bool {
+		return r.organizationID != organizationID || r.userID != employee.ID
+	})
+	return nil
+}
+
+func (s *Storage) checkOrganizationAccess(organizationID, username string) error {
+	if _, exist := s.userByName(username); !exist {
+		return storage.ErrNoUser
+	}
+	if _, ok := s.organizations[organizationID]; !ok {
+		return storage.ErrNoOrganization
+	}
+	if !s.isUserResponsibleForOrganization(username, organizationID) {
+		return storage.ErrRights
+	}
+	return nil
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	kept := items[:0]
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func sortOrganizations(organizations []models.Organization) {
+	sort.Slice(organizations, func(i, j int) bool {
+		if organizations[i].Name != organizations[j].Name {
+			return organizations[i].Name < organizations[j].Name
+		}
+		return organizations[i].ID < organizations[j].ID
+	})
+}
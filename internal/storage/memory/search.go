@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Веса полей повторяют setweight в миграции: совпадение в названии важнее совпадения в описании
+const (
+	nameWeight        = 1.0
+	descriptionWeight = 0.4
+)
+
+// tokenize приводит текст к нижнему регистру и режет по всему, что не буква и не цифра
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchTender проверяет, что в тендере встречаются все слова запроса, и считает релевантность и подсвеченный фрагмент
+func matchTender(tender models.Tender, terms []string) (float64, string, bool) {
+	name := counts(tokenize(tender.Name))
+	description := counts(tokenize(tender.Description))
+
+	rank := 0.0
+	for _, term := range terms {
+		if name[term] == 0 && description[term] == 0 {
+			return 0, "", false
+		}
+		rank += nameWeight*float64(name[term]) + descriptionWeight*float64(description[term])
+	}
+	return rank / float64(len(terms)), highlight(tender.Description, terms), true
+}
+
+func counts(tokens []string) map[string]int {
+	result := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		result[token]++
+	}
+	return result
+}
+
+// highlight оборачивает совпавшие слова в <b></b>, как ts_headline с StartSel/StopSel.
+// Остальной текст экранируется, как escapedDescription в запросе Postgres
+func highlight(text string, terms []string) string {
+	var sb strings.Builder
+	word := []rune{}
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		original := html.EscapeString(string(word))
+		matched := false
+		for _, term := range terms {
+			if strings.ToLower(string(word)) == term {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			sb.WriteString("<b>" + original + "</b>")
+		} else {
+			sb.WriteString(original)
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		sb.WriteString(html.EscapeString(string(r)))
+	}
+	flush()
+	return sb.String()
+}
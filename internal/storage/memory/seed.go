@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"os"
+)
+
+// Seed - начальные данные для хранилища в памяти. Пароли сотрудников задаются открытым текстом и хешируются при загрузке.
+type Seed struct {
+	Employees     []SeedEmployee        `json:"employees"`
+	Organizations []models.Organization `json:"organizations"`
+	Responsibles  []SeedResponsible     `json:"responsibles"`
+	Tenders       []models.Tender       `json:"tenders"`
+	Bids          []models.Bid          `json:"bids"`
+}
+
+type SeedEmployee struct {
+	models.User
+	Password string `json:"password"`
+}
+
+type SeedResponsible struct {
+	OrganizationID string `json:"organizationId"`
+	Username       string `json:"username"`
+}
+
+// LoadFile читает Seed из JSON-файла и добавляет данные в хранилище
+func (s *Storage) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading seed file: %w", err)
+	}
+
+	var seed Seed
+	if err = json.Unmarshal(data, &seed); err != nil {
+		return fmt.Errorf("error parsing seed file: %w", err)
+	}
+	return s.Load(seed)
+}
+
+func (s *Storage) Load(seed Seed) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range seed.Employees {
+		var passwordHash string
+		if e.Password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(e.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			passwordHash = string(hash)
+		}
+		s.employees[e.ID] = employee{user: e.User, passwordHash: passwordHash}
+	}
+	for _, organization := range seed.Organizations {
+		s.organizations[organization.ID] = organization
+	}
+	for _, r := range seed.Responsibles {
+		user, exist := s.userByName(r.Username)
+		if !exist {
+			return fmt.Errorf("seed responsible %s is not an employee", r.Username)
+		}
+		if _, ok := s.organizations[r.OrganizationID]; !ok {
+			return fmt.Errorf("seed organization %s does not exist", r.OrganizationID)
+		}
+		s.responsibles = append(s.responsibles, responsible{organizationID: r.OrganizationID, userID: user.ID})
+	}
+	for _, tender := range seed.Tenders {
+		s.tenders[tender.ID] = tender
+	}
+	for _, bid := range seed.Bids {
+		s.bids[bid.ID] = bid
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/cursor"
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (s *Storage) GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := filter.Status
+	if len(statuses) == 0 {
+		statuses = []string{"Published"}
+	}
+	terms := tokenize(filter.Query)
+
+	var tenders []models.Tender
+	for _, tender := range s.tenders {
+		if !slices.Contains(statuses, tender.Status) {
+			continue
+		}
+		if tender.Status != "Published" && !s.isUserResponsibleForOrganization(filter.Username, tender.OrganizationID) {
+			continue
+		}
+		if !matchFilter(tender, filter) {
+			continue
+		}
+		if len(terms) > 0 {
+			rank, snippet, ok := matchTender(tender, terms)
+			if !ok {
+				continue
+			}
+			tender.Rank, tender.Snippet = rank, snippet
+		}
+		tenders = append(tenders, tender)
+	}
+	less := func(a, b models.Tender) bool {
+		return lessTender(a, b, filter)
+	}
+	sort.Slice(tenders, func(i, j int) bool {
+		return less(tenders[i], tenders[j])
+	})
+	probe := func(keys []string) (models.Tender, error) {
+		return tenderKey(keys, filter)
+	}
+	return pageAfter(tenders, models.Page{Limit: filter.Limit, Offset: filter.Offset, After: filter.After}, probe, less)
+}
+
+// tenderKey восстанавливает из ключа models.TenderFilter.Key поля, по которым сравнивает lessTender
+func tenderKey(keys []string, filter models.TenderFilter) (models.Tender, error) {
+	var tender models.Tender
+	var err error
+	switch {
+	case filter.Sort == models.TenderSortCreatedAt || filter.Sort == models.TenderSortUpdatedAt:
+		if len(keys) != 2 {
+			return tender, cursor.ErrInvalid
+		}
+		tender.CreatedAt, err = models.ParseKeyTime(keys[0])
+		tender.UpdatedAt, tender.ID = tender.CreatedAt, keys[1]
+	case filter.Sort == "" && filter.Query != "":
+		if len(keys) != 3 {
+			return tender, cursor.ErrInvalid
+		}
+		tender.Rank, err = strconv.ParseFloat(keys[0], 64)
+		tender.Name, tender.ID = keys[1], keys[2]
+	default:
+		return tenderByNameKey(keys)
+	}
+	if err != nil {
+		return tender, cursor.ErrInvalid
+	}
+	return tender, nil
+}
+
+func matchFilter(tender models.Tender, filter models.TenderFilter) bool {
+	if len(filter.ServiceType) > 0 && !slices.Contains(filter.ServiceType, tender.ServiceType) {
+		return false
+	}
+	if filter.OrganizationID != "" && tender.OrganizationID != filter.OrganizationID {
+		return false
+	}
+	if !filter.CreatedFrom.IsZero() && tender.CreatedAt.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedTo.IsZero() && tender.CreatedAt.After(filter.CreatedTo) {
+		return false
+	}
+	if !filter.UpdatedFrom.IsZero() && tender.UpdatedAt.Before(filter.UpdatedFrom) {
+		return false
+	}
+	if !filter.UpdatedTo.IsZero() && tender.UpdatedAt.After(filter.UpdatedTo) {
+		return false
+	}
+	return true
+}
+
+// lessTender повторяет ORDER BY из storage.tenderOrder
+func lessTender(a, b models.Tender, filter models.TenderFilter) bool {
+	var cmp int
+	switch {
+	case filter.Sort == models.TenderSortCreatedAt:
+		cmp = a.CreatedAt.Compare(b.CreatedAt)
+	case filter.Sort == models.TenderSortUpdatedAt:
+		cmp = a.UpdatedAt.Compare(b.UpdatedAt)
+	case filter.Sort == "" && filter.Query != "":
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		cmp = strings.Compare(a.Name, b.Name)
+		if cmp == 0 {
+			return a.ID < b.ID
+		}
+		return cmp < 0
+	default:
+		cmp = strings.Compare(a.Name, b.Name)
+	}
+	if filter.Desc {
+		cmp = -cmp
+	}
+	if cmp == 0 {
+		return a.ID < b.ID
+	}
+	return cmp < 0
+}
+
+func (s *Storage) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.userByName(username); !exist {
+		return models.Tender{}, storage.ErrNoUser
+	}
+	tender, ok := s.tenders[tenderId]
+	if !ok {
+		return models.Tender{}, storage.ErrNoTender
+	}
+	if !s.isUserResponsibleForTender(username, tenderId) {
+		return models.Tender{}, storage.ErrRights
+	}
+	if expectedVersion != 0 && tender.Version != expectedVersion {
+		return models.Tender{}, storage.ErrVersionMismatch
+	}
+
+	if status != "" {
+		if !models.CanTransitionTender(tender.Status, status) {
+			return models.Tender{}, storage.ErrInvalidTransition
+		}
+		tender.Status = status
+	}
+	if tenderName != "" {
+		tender.Name = tenderName
+	}
+	if description != "" {
+		tender.Description = description
+	}
+	if serviceType != "" {
+		tender.ServiceType = serviceType
+	}
+
+	s.archiveTender(tenderId)
+	tender = s.updateTender(tender)
+	s.recordAudit(ctx, models.AuditEvent{Actor: username, Action: models.AuditEdit, EntityType: models.AuditTender, EntityID: tenderId, VersionBefore: tender.Version - 1, VersionAfter: tender.Version})
+	return tender, nil
+}
+
+func (s *Storage) GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.checkTenderHistoryAccess(tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tenders := append([]models.Tender{s.tenders[tenderID]}, s.tenderHistory[tenderID]...)
+	sort.Slice(tenders, func(i, j int) bool {
+		return tenders[i].Version > tenders[j].Version
+	})
+	return page(tenders, limit, offset), nil
+}
+
+func (s *Storage) DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.checkTenderHistoryAccess(tenderID, username); err != nil {
+		return models.VersionDiff{}, err
+	}
+
+	versions := append([]models.Tender{s.tenders[tenderID]}, s.tenderHistory[tenderID]...)
+	versionOf := func(t models.Tender) int { return t.Version }
+	fromTender, ok := findVersion(versions, from, versionOf)
+	if !ok {
+		return models.VersionDiff{}, storage.ErrNoVersion
+	}
+	toTender, ok := findVersion(versions, to, versionOf)
+	if !ok {
+		return models.VersionDiff{}, storage.ErrNoVersion
+	}
+	return models.DiffTenders(fromTender, toTender), nil
+}
+
+func (s *Storage) checkTenderHistoryAccess(tenderID, username string) error {
+	if _, exist := s.userByName(username); !exist {
+		return storage.ErrNoUser
+	}
+	if _, ok := s.tenders[tenderID]; !ok {
+		return storage.ErrNoTender
+	}
+	if !s.isUserResponsibleForTender(username, tenderID) {
+		return storage.ErrRights
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"avito.go/migrations"
+	"avito.go/pkg/logger"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+	"io/fs"
+	"net"
+	"time"
+)
+
+// MigrateCommands - поддерживаемые команды подкоманды migrate
+var MigrateCommands = []string{"up", "down", "status"}
+
+// Migrate выполняет goose-команду над встроенными миграциями; версия схемы хранится в goose_db_version
+func Migrate(ctx context.Context, db *sql.DB, command string, args ...string) error {
+	goose.SetBaseFS(migrations.FS)
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+	if err := goose.RunContext(ctx, command, db, ".", args...); err != nil {
+		return fmt.Errorf("error running migrations %s: %w", command, err)
+	}
+	return nil
+}
+
+// LatestMigration - версия последней встроенной миграции, до которой должна быть доведена схема.
+// Версия берётся из имён файлов, без глобального состояния goose.
+func LatestMigration() (int64, error) {
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, file := range files {
+		version, err := goose.NumericComponent(file)
+		if err != nil {
+			return 0, err
+		}
+		latest = max(latest, version)
+	}
+	if latest == 0 {
+		return 0, errors.New("no embedded migrations")
+	}
+	return latest, nil
+}
+
+// MigrateUp применяет миграции до старта сервера. Повторяет попытку каждые interval, только пока база недоступна;
+// ошибка самой миграции возвращается сразу, чтобы сервис не работал на недоделанной схеме.
+func (db *DB) MigrateUp(ctx context.Context, interval time.Duration) error {
+	for {
+		err := Migrate(ctx, db.DB, "up")
+		if err == nil || !isConnectionError(err) {
+			return err
+		}
+		logger.Log.Error("database is unavailable, retrying migrations", zap.Error(err), zap.Duration("interval", interval))
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("migrations were not applied: %w", err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isConnectionError - ошибка соединения с базой, а не ответ сервера на SQL миграции
+func isConnectionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) || errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn)
+}
@@ -0,0 +1,372 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"avito.go/pkg/uuid"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Masterminds/squirrel"
+)
+
+// CreateOrganization создаёт организацию и назначает создателя её первым ответственным
+func (db *DB) CreateOrganization(ctx context.Context, organization models.Organization, username string) error {
+	return db.withTx(ctx, func(tx *Tx) error {
+		userID, err := getUserID(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+
+		query := squirrel.Insert("organization").
+			Columns("id", "name", "description", "type", "created_at", "updated_at").
+			Values(organization.ID, organization.Name, organization.Description, nullable(organization.Type), organization.CreatedAt, organization.UpdatedAt).
+			PlaceholderFormat(squirrel.Dollar)
+
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return queryError(ctx, err)
+		}
+
+		return addResponsible(ctx, tx, organization.ID, userID)
+	})
+}
+
+func (db *DB) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	query := squirrel.Select("id", "name", "COALESCE(description, '')", "COALESCE(type::text, '')", "created_at", "updated_at").
+		From("organization").
+		OrderBy("name", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	organizations := []models.Organization{}
+	for rows.Next() {
+		var organization models.Organization
+		if err = rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.Description,
+			&organization.Type,
+			&organization.CreatedAt,
+			&organization.UpdatedAt); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+	return organizations, rows.Err()
+}
+
+func (db *DB) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	return organizationByID(ctx, db, organizationID)
+}
+
+func (db *DB) EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.Organization{}, ErrNoUser
+	}
+	if _, err := organizationByID(ctx, db, organizationID); err != nil {
+		return models.Organization{}, err
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, organizationID)
+	if !check {
+		return models.Organization{}, ErrRights
+	}
+
+	query := squirrel.Update("organization").
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": organizationID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if name != "" {
+		query = query.Set("name", name)
+	}
+	if description != "" {
+		query = query.Set("description", description)
+	}
+	if organizationType != "" {
+		query = query.Set("type", organizationType)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return models.Organization{}, err
+	}
+	_, err = db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return models.Organization{}, queryError(ctx, err)
+	}
+
+	return organizationByID(ctx, db, organizationID)
+}
+
+func (db *DB) DeleteOrganization(ctx context.Context, organizationID, username string) error {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return ErrNoUser
+	}
+	if _, err := organizationByID(ctx, db, organizationID); err != nil {
+		return err
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, organizationID)
+	if !check {
+		return ErrRights
+	}
+
+	query := squirrel.Delete("organization").
+		Where(squirrel.Eq{"id": organizationID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return nil
+}
+
+func (db *DB) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	if _, err := organizationByID(ctx, db, organizationID); err != nil {
+		return nil, err
+	}
+
+	query := squirrel.Select(employeeColumns...).
+		From(`organization_responsible "or"`).
+		Join(`employee e ON "or".user_id = e.id`).
+		Where(squirrel.Eq{`"or".organization_id`: organizationID}).
+		OrderBy("e.username").
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	return scanUsers(rows)
+}
+
+// AddOrganizationResponsible назначает employeeUsername ответственным; право есть только у текущих ответственных
+func (db *DB) AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return ErrNoUser
+	}
+	if _, err := organizationByID(ctx, db, organizationID); err != nil {
+		return err
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, organizationID)
+	if !check {
+		return ErrRights
+	}
+
+	return db.withTx(ctx, func(tx *Tx) error {
+		employeeID, err := getUserID(ctx, tx, employeeUsername)
+		if err != nil {
+			return err
+		}
+		responsible, err := countResponsible(ctx, tx, organizationID, employeeID)
+		if err != nil {
+			return err
+		}
+		if responsible > 0 {
+			return ErrAlreadyResponsible
+		}
+		return addResponsible(ctx, tx, organizationID, employeeID)
+	})
+}
+
+// RemoveOrganizationResponsible снимает employeeUsername с ответственности, не оставляя организацию без ответственных
+func (db *DB) RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return ErrNoUser
+	}
+	if _, err := organizationByID(ctx, db, organizationID); err != nil {
+		return err
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, organizationID)
+	if !check {
+		return ErrRights
+	}
+
+	return db.withTx(ctx, func(tx *Tx) error {
+		employeeID, err := getUserID(ctx, tx, employeeUsername)
+		if errors.Is(err, ErrNoUser) {
+			// несуществующий сотрудник тем более не ответственный; ErrNoUser означает неизвестного автора запроса
+			return ErrNotResponsible
+		}
+		if err != nil {
+			return err
+		}
+
+		// блокируем всех ответственных организации, чтобы два параллельных удаления не оставили её пустой
+		lock := squirrel.Select("user_id").
+			From("organization_responsible").
+			Where(squirrel.Eq{"organization_id": organizationID}).
+			Suffix("FOR UPDATE").
+			PlaceholderFormat(squirrel.Dollar)
+
+		sqlQuery, args, err := lock.ToSql()
+		if err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return queryError(ctx, err)
+		}
+		total, found := 0, false
+		for rows.Next() {
+			var userID string
+			if err = rows.Scan(&userID); err != nil {
+				rows.Close()
+				return err
+			}
+			total++
+			found = found || userID == employeeID
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return queryError(ctx, err)
+		}
+
+		if !found {
+			return ErrNotResponsible
+		}
+		if total <= 1 {
+			return ErrLastResponsible
+		}
+
+		query := squirrel.Delete("organization_responsible").
+			Where(squirrel.Eq{"organization_id": organizationID, "user_id": employeeID}).
+			PlaceholderFormat(squirrel.Dollar)
+
+		sqlQuery, args, err = query.ToSql()
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return queryError(ctx, err)
+		}
+		return nil
+	})
+}
+
+func organizationByID(ctx context.Context, r runner, organizationID string) (models.Organization, error) {
+	query := squirrel.Select("id", "name", "COALESCE(description, '')", "COALESCE(type::text, '')", "created_at", "updated_at").
+		From("organization").
+		Where(squirrel.Eq{"id": organizationID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	var organization models.Organization
+	err = r.QueryRowContext(ctx, sqlQuery, args...).Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.Description,
+		&organization.Type,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Organization{}, ErrNoOrganization
+		}
+		return models.Organization{}, queryError(ctx, err)
+	}
+	return organization, nil
+}
+
+func getUserID(ctx context.Context, r runner, username string) (string, error) {
+	query := squirrel.Select("id").
+		From("employee").
+		Where(squirrel.Eq{"username": username}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var userID string
+	err = r.QueryRowContext(ctx, sqlQuery, args...).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoUser
+		}
+		return "", queryError(ctx, err)
+	}
+	return userID, nil
+}
+
+func countResponsible(ctx context.Context, r runner, organizationID, userID string) (int, error) {
+	query := squirrel.Select("COUNT(*)").
+		From("organization_responsible").
+		Where(squirrel.Eq{"organization_id": organizationID, "user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, queryError(ctx, err)
+	}
+	return count, nil
+}
+
+func addResponsible(ctx context.Context, r runner, organizationID, userID string) error {
+	query := squirrel.Insert("organization_responsible").
+		Columns("id", "organization_id", "user_id").
+		Values(uuid.GenerateCorrelationID(), organizationID, userID).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return nil
+}
+
+// nullable превращает пустую строку в NULL для необязательных enum-колонок
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
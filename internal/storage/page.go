@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"avito.go/pkg/cursor"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"strconv"
+)
+
+// Типы значений в ключе курсора
+const (
+	keyText = iota
+	keyTime
+	keyFloat
+)
+
+// keyColumn - колонка упорядочивания выборки.
+// name попадает в ORDER BY (может быть алиасом), expr с args - в условие курсора; пустой expr равен name.
+type keyColumn struct {
+	name string
+	expr string
+	args []interface{}
+	kind int
+	desc bool
+}
+
+// paginate упорядочивает выборку по columns и ограничивает её страницей:
+// с курсором - записями строго после ключа page.After, без него - смещением page.Offset
+func paginate(query squirrel.SelectBuilder, columns []keyColumn, page models.Page) (squirrel.SelectBuilder, error) {
+	orderBy := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.desc {
+			orderBy = append(orderBy, column.name+" DESC")
+		} else {
+			orderBy = append(orderBy, column.name)
+		}
+	}
+	query = query.OrderBy(orderBy...).Limit(uint64(page.Limit))
+
+	if page.After == nil {
+		return query.Offset(uint64(page.Offset)), nil
+	}
+	after, err := keysetAfter(columns, page.After)
+	if err != nil {
+		return query, err
+	}
+	return query.Where(after), nil
+}
+
+// keysetAfter строит условие "строго после ключа":
+// c1 > v1 OR (c1 = v1 AND (c2 > v2 OR (c2 = v2 AND ...))), для DESC-колонок сравнение обратное
+func keysetAfter(columns []keyColumn, keys []string) (squirrel.Sqlizer, error) {
+	if len(keys) != len(columns) {
+		return nil, cursor.ErrInvalid
+	}
+
+	var cond squirrel.Sqlizer
+	for i := len(columns) - 1; i >= 0; i-- {
+		column := columns[i]
+		value, err := keyValue(column.kind, keys[i])
+		if err != nil {
+			return nil, err
+		}
+		expr := column.expr
+		if expr == "" {
+			expr = column.name
+		}
+		op := " > ?"
+		if column.desc {
+			op = " < ?"
+		}
+
+		next := squirrel.Expr(expr+op, append(column.args, value)...)
+		if cond == nil {
+			cond = next
+			continue
+		}
+		cond = squirrel.Or{next, squirrel.And{squirrel.Expr(expr+" = ?", append(column.args, value)...), cond}}
+	}
+	return cond, nil
+}
+
+func keyValue(kind int, key string) (interface{}, error) {
+	switch kind {
+	case keyTime:
+		t, err := models.ParseKeyTime(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", cursor.ErrInvalid, err)
+		}
+		return t, nil
+	case keyFloat:
+		f, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", cursor.ErrInvalid, err)
+		}
+		return f, nil
+	}
+	return key, nil
+}
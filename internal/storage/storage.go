@@ -9,235 +9,343 @@ import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"log"
 	"math"
-	"sort"
 	"time"
 )
 
-type Storage interface {
-	Add(ctx context.Context, entity interface{}, username string, key int) error
-	GetMy(ctx context.Context, limit, offset int, username string, key int) (interface{}, error)
-	GetStatus(ctx context.Context, Id, username string, key int) (string, error)
-	UpdateStatus(ctx context.Context, Id, status, username string, key int) (interface{}, error)
-	RollbackVersion(ctx context.Context, Id, version, username string, key int) (interface{}, error)
+type TenderRepository interface {
+	AddTender(ctx context.Context, tender models.Tender, username string) error
+	GetMyTenders(ctx context.Context, page models.Page, username string) ([]models.Tender, error)
+	GetTenderStatus(ctx context.Context, tenderID, username string) (string, error)
+	UpdateTenderStatus(ctx context.Context, tenderID, status, username string) (models.Tender, error)
+	RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error)
+	CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error)
+
+	GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error)
+	EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error)
+	GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error)
+	DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error)
+}
 
-	GetTenders(ctx context.Context, limit, offset int, serviceType []string) ([]models.Tender, error)
-	EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string) (models.Tender, error)
+type BidRepository interface {
+	AddBid(ctx context.Context, bid models.Bid, userID string) error
+	GetMyBids(ctx context.Context, page models.Page, username string) ([]models.Bid, error)
+	GetBidStatus(ctx context.Context, bidID, username string) (string, error)
+	UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error)
+	RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error)
 
-	GetTenderBids(ctx context.Context, tenderId, username string, limit, offset int) ([]models.Bid, error)
+	GetTenderBids(ctx context.Context, tenderId, username string, page models.Page) ([]models.Bid, error)
 	SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) // Отправить решение по биду
-	EditBid(ctx context.Context, bidId, username, bidName, description, status string) (models.Bid, error)
+	EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error)
+	GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error)
+	DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error)
 
 	AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) // отправить отзыв по предложению.
-	GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername, limit, offset int) ([]models.FeedBack, error)
+	GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, page models.Page) ([]models.FeedBack, error)
+}
+
+type AuthStorage interface {
+	GetUserCredentials(ctx context.Context, username string) (models.User, string, error)
+}
+
+type OrganizationStorage interface {
+	CreateOrganization(ctx context.Context, organization models.Organization, username string) error
+	GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error)
+	GetOrganization(ctx context.Context, organizationID string) (models.Organization, error)
+	EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error)
+	DeleteOrganization(ctx context.Context, organizationID, username string) error
+
+	GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error)
+	AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error
+	RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error
+}
+
+type EmployeeStorage interface {
+	GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error)
+	GetEmployee(ctx context.Context, username string) (models.User, error)
+	GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error)
+}
+
+type AuditStorage interface {
+	GetAuditEvents(ctx context.Context, filter models.AuditFilter, page models.Page, username string) ([]models.AuditEvent, error)
 }
 
+// AttachmentStorage - сведения о файлах тендеров и предложений; само содержимое хранит blob.Store.
+// Чтение доступно тем же сотрудникам, что видят предложения тендера в GetTenderBids.
+type AttachmentStorage interface {
+	CanAttach(ctx context.Context, entityType, entityID, username string) error
+	AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error)
+	GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error)
+	GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error)
+}
+
+// HealthStorage - проверки зависимостей для /api/health/ready
+type HealthStorage interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (current, latest int64, err error)
+}
+
+// Storage - единый интерфейс хранилища; реализуется Postgres (DB) и памятью (пакет memory)
+type Storage interface {
+	TenderRepository
+	BidRepository
+	AuthStorage
+	OrganizationStorage
+	EmployeeStorage
+	AuditStorage
+	AttachmentStorage
+	HealthStorage
+
+	Close() error
+}
+
+var _ Storage = (*DB)(nil)
+
 type DB struct {
-	DB *sql.DB
+	DB     *sql.DB
+	latest int64 // Версия последней встроенной миграции, вычисляется один раз при открытии
 }
 
-func NewStorage(DatabaseDSN string) *DB {
-	fmt.Println("STORAGE", DatabaseDSN)
-	db, err := sql.Open("pgx", DatabaseDSN)
+// NewStorage открывает пул соединений; миграции применяет MigrateUp
+func NewStorage(DatabaseDSN string) (*DB, error) {
+	latest, err := LatestMigration()
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	err = CreateTable(db)
+	db, err := sql.Open("pgx", DatabaseDSN)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return &DB{db}
+	return &DB{DB: db, latest: latest}, nil
 }
 
-func (db *DB) Add(ctx context.Context, entity interface{}, username string, key int) error {
-	switch key {
-	case 1:
-		bid, ok := entity.(models.Bid)
-		if !ok {
-			return errors.New("invalid entity type")
-		}
-		exist, _ := GetUserByID(ctx, db, username)
-		if !exist {
-			return ErrNoUser
-		}
-		check, _ := isUserResponsibleForTender(ctx, db, GetUsernameByID(ctx, db, username), bid.TenderID)
-		if check {
+func (db *DB) Close() error {
+	return db.DB.Close()
+}
+
+// AddBid создаёт предложение от имени сотрудника userID. Предложение пользователя он подаёт только от себя,
+// предложение организации - только если отвечает за неё.
+func (db *DB) AddBid(ctx context.Context, bid models.Bid, userID string) error {
+	exist, _ := GetUserByID(ctx, db, userID)
+	if !exist {
+		return ErrNoUser
+	}
+	author := GetUsernameByID(ctx, db, userID)
+	if bid.AuthorType == "Organization" {
+		responsible, _ := IsUserResponsibleForOrganization(ctx, db, author, bid.AuthorID)
+		if !responsible {
 			return ErrRights
 		}
-		TenderExist, _ := GetTender(ctx, db, bid.TenderID)
-		if !TenderExist {
-			return ErrNoTender
-		}
+	} else if bid.AuthorID != userID {
+		return ErrRights
+	}
+	check, _ := isUserResponsibleForTender(ctx, db, author, bid.TenderID)
+	if check {
+		return ErrRights
+	}
+	TenderExist, _ := GetTender(ctx, db, bid.TenderID)
+	if !TenderExist {
+		return ErrNoTender
+	}
+	passed, err := submissionDeadlinePassed(ctx, db, bid.TenderID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if passed {
+		return ErrDeadlinePassed
+	}
 
-		query := squirrel.Insert("bid").
-			Columns("id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at").
-			Values(bid.ID, bid.Name, bid.Description, bid.Status, bid.TenderID, bid.AuthorType, bid.AuthorID, bid.Version, bid.CreatedAt).
-			PlaceholderFormat(squirrel.Dollar)
+	query := squirrel.Insert("bid").
+		Columns("id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at").
+		Values(bid.ID, bid.Name, bid.Description, bid.Status, bid.TenderID, bid.AuthorType, bid.AuthorID, bid.Version, bid.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar)
 
-		sql, args, err := query.ToSql()
-		if err != nil {
-			return err
-		}
-		_, err = db.DB.ExecContext(ctx, sql, args...)
-		if err != nil {
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.withTx(ctx, func(tx *Tx) error {
+		if _, err := tx.ExecContext(ctx, sqlQuery, args...); err != nil {
 			return err
 		}
-		return nil
-	case 2:
-		tender, ok := entity.(models.Tender)
-		if !ok {
-			return errors.New("invalid entity type")
-		}
-		exist, _ := GetUser(ctx, db, username)
-		if !exist {
-			return ErrNoUser
-		}
-		check, _ := IsUserResponsibleForOrganization(ctx, db, username, tender.OrganizationID)
-		if !check {
-			return ErrRights
-		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: author, Action: models.AuditCreate, EntityType: models.AuditBid, EntityID: bid.ID, VersionAfter: int(bid.Version)})
+	})
+}
 
-		query := squirrel.Insert("tender").
-			Columns("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at").
-			Values(tender.ID, tender.Name, tender.Description, tender.ServiceType, tender.Status, tender.OrganizationID, tender.Version, tender.CreatedAt).
-			PlaceholderFormat(squirrel.Dollar)
+func (db *DB) AddTender(ctx context.Context, tender models.Tender, username string) error {
+	exist, _ := GetUser(ctx, db, username)
+	if !exist {
+		return ErrNoUser
+	}
+	check, _ := IsUserResponsibleForOrganization(ctx, db, username, tender.OrganizationID)
+	if !check {
+		return ErrRights
+	}
 
-		sql, args, err := query.ToSql()
-		if err != nil {
-			return err
-		}
-		_, err = db.DB.ExecContext(ctx, sql, args...)
-		if err != nil {
+	query := squirrel.Insert("tender").
+		Columns("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "submission_deadline", "decision_deadline").
+		Values(tender.ID, tender.Name, tender.Description, tender.ServiceType, tender.Status, tender.OrganizationID, tender.Version, tender.CreatedAt, tender.SubmissionDeadline, tender.DecisionDeadline).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.withTx(ctx, func(tx *Tx) error {
+		if _, err := tx.ExecContext(ctx, sqlQuery, args...); err != nil {
 			return err
 		}
-		return nil
-	}
-	return nil
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditCreate, EntityType: models.AuditTender, EntityID: tender.ID, VersionAfter: tender.Version})
+	})
 }
 
-func (db *DB) GetMy(ctx context.Context, limit, offset int, username string, key int) (interface{}, error) {
-	switch key {
-	case 1:
-		exist, _ := GetUser(ctx, db, username)
-		if !exist && username != "" {
-			return nil, ErrNoUser
-		}
+func (db *DB) GetMyBids(ctx context.Context, page models.Page, username string) ([]models.Bid, error) {
+	exist, _ := GetUser(ctx, db, username)
+	if !exist && username != "" {
+		return nil, ErrNoUser
+	}
 
-		var bids []models.Bid
+	var bids []models.Bid
 
-		query := squirrel.Select("bid.id", "bid.name", "bid.description", "bid.status", "bid.tender_id", "bid.author_type", "bid.author_id", "bid.version", "bid.created_at", "bid.updated_at").
-			From("bid").
-			Join("employee e ON bid.author_id = e.id").
-			Where(squirrel.Eq{"e.username": username}).
-			Limit(uint64(limit)).
-			Offset(uint64(offset)).
-			PlaceholderFormat(squirrel.Dollar)
+	query := squirrel.Select("bid.id", "bid.name", "bid.description", "bid.status", "bid.tender_id", "bid.author_type", "bid.author_id", "bid.version", "bid.created_at", "bid.updated_at").
+		From("bid").
+		Where(bidAuthoredBy(username)).
+		PlaceholderFormat(squirrel.Dollar)
+	query, err := paginate(query, []keyColumn{{name: "bid.name"}, {name: "bid.id"}}, page)
+	if err != nil {
+		return nil, err
+	}
 
-		sql, args, err := query.ToSql()
-		if err != nil {
-			return nil, err
-		}
-		rows, err := db.DB.QueryContext(ctx, sql, args...)
-		if err != nil {
-			return nil, err
-		}
-		for rows.Next() {
-			var bid models.Bid
-			if err = rows.Scan(
-				&bid.ID,
-				&bid.Name,
-				&bid.Description,
-				&bid.Status,
-				&bid.TenderID,
-				&bid.AuthorType,
-				&bid.AuthorID,
-				&bid.Version,
-				&bid.CreatedAt,
-				&bid.UpdatedAt); err != nil {
-				return nil, err
-			}
-			bids = append(bids, bid)
-		}
-		defer rows.Close()
-		sort.Slice(bids, func(i, j int) bool {
-			return bids[i].Name < bids[j].Name
-		})
-		return bids, nil
-	case 2:
-		exist, _ := GetUser(ctx, db, username)
-		if !exist && username != "" {
-			return nil, ErrNoUser
-		}
-
-		var tenders []models.Tender
-
-		query := squirrel.Select("tender.id", "tender.name", "tender.description", "tender.service_type", "tender.status", "tender.organization_id", "tender.version", "tender.created_at", "tender.updated_at").
-			From("tender").
-			Join(`organization_responsible "or" ON tender.organization_id = "or".organization_id`).
-			Join("employee e ON \"or\".user_id = e.id").
-			Where(squirrel.Eq{"e.username": username}).
-			Limit(uint64(limit)).
-			Offset(uint64(offset)).
-			PlaceholderFormat(squirrel.Dollar)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
 
-		sql, args, err := query.ToSql()
-		if err != nil {
+	for rows.Next() {
+		var bid models.Bid
+		if err = rows.Scan(
+			&bid.ID,
+			&bid.Name,
+			&bid.Description,
+			&bid.Status,
+			&bid.TenderID,
+			&bid.AuthorType,
+			&bid.AuthorID,
+			&bid.Version,
+			&bid.CreatedAt,
+			&bid.UpdatedAt); err != nil {
 			return nil, err
 		}
-		rows, err := db.DB.QueryContext(ctx, sql, args...)
-		if err != nil {
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
+
+func (db *DB) GetMyTenders(ctx context.Context, page models.Page, username string) ([]models.Tender, error) {
+	exist, _ := GetUser(ctx, db, username)
+	if !exist && username != "" {
+		return nil, ErrNoUser
+	}
+
+	var tenders []models.Tender
+
+	query := squirrel.Select("tender.id", "tender.name", "tender.description", "tender.service_type", "tender.status", "tender.organization_id", "tender.version", "tender.created_at", "tender.updated_at", "tender.submission_deadline", "tender.decision_deadline").
+		From("tender").
+		Join(`organization_responsible "or" ON tender.organization_id = "or".organization_id`).
+		Join("employee e ON \"or\".user_id = e.id").
+		Where(squirrel.Eq{"e.username": username}).
+		PlaceholderFormat(squirrel.Dollar)
+	query, err := paginate(query, []keyColumn{{name: "tender.name"}, {name: "tender.id"}}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tender models.Tender
+		if err = rows.Scan(
+			&tender.ID,
+			&tender.Name,
+			&tender.Description,
+			&tender.ServiceType,
+			&tender.Status,
+			&tender.OrganizationID,
+			&tender.Version,
+			&tender.CreatedAt,
+			&tender.UpdatedAt,
+			&tender.SubmissionDeadline,
+			&tender.DecisionDeadline); err != nil {
 			return nil, err
 		}
-		for rows.Next() {
-			var tender models.Tender
-			if err = rows.Scan(
-				&tender.ID,
-				&tender.Name,
-				&tender.Description,
-				&tender.ServiceType,
-				&tender.Status,
-				&tender.OrganizationID,
-				&tender.Version,
-				&tender.CreatedAt,
-				&tender.UpdatedAt); err != nil {
-				return nil, err
-			}
-			tenders = append(tenders, tender)
-		}
-		fmt.Println(tenders)
-		defer rows.Close()
-		sort.Slice(tenders, func(i, j int) bool {
-			return tenders[i].Name < tenders[j].Name
-		})
-		return tenders, nil
+		tenders = append(tenders, tender)
 	}
-	return nil, nil
+	return tenders, rows.Err()
 }
 
-func (db *DB) GetStatus(ctx context.Context, Id, username string, key int) (string, error) {
+func (db *DB) GetBidStatus(ctx context.Context, bidID, username string) (string, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
 		return "", ErrNoUser
 	}
-	var table string
-	switch key {
-	case 1:
-		BidExist, _ := GetBid(ctx, db, Id)
-		if !BidExist {
-			return "", ErrNoBid
+	BidExist, _ := GetBid(ctx, db, bidID)
+	if !BidExist {
+		return "", ErrNoBid
+	}
+
+	status, err := entityStatus(ctx, db, "bid", bidID)
+	if err != nil {
+		return "", err
+	}
+	if status == "Created" || status == "Canceled" {
+		check, _ := isUserResponsibleForBid(ctx, db, username, bidID)
+		if !check {
+			return "", ErrRights
 		}
-		table = "bid"
-	case 2:
-		TenderExist, _ := GetTender(ctx, db, Id)
-		if !TenderExist {
-			return "", ErrNoTender
+	}
+	return status, nil
+}
+
+func (db *DB) GetTenderStatus(ctx context.Context, tenderID, username string) (string, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return "", ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return "", ErrNoTender
+	}
+
+	status, err := entityStatus(ctx, db, "tender", tenderID)
+	if err != nil {
+		return "", err
+	}
+	if status == "Created" || status == "Closed" {
+		check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+		if !check {
+			return "", ErrRights
 		}
-		table = "tender"
 	}
+	return status, nil
+}
+
+func entityStatus(ctx context.Context, db *DB, table, id string) (string, error) {
 	query := squirrel.Select("status").
 		From(table).
-		Where(squirrel.Eq{"id": Id}).
+		Where(squirrel.Eq{"id": id}).
 		PlaceholderFormat(squirrel.Dollar)
 
 	sql, args, err := query.ToSql()
@@ -246,256 +354,307 @@ func (db *DB) GetStatus(ctx context.Context, Id, username string, key int) (stri
 	}
 
 	var status string
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&status)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&status)
 	if err != nil {
-		return "", fmt.Errorf("error executing query: %w", err)
-	}
-
-	switch key {
-	case 1:
-		if status == "Created" || status == "Canceled" {
-			check, err := isUserResponsibleForBid(ctx, db, username, Id)
-			fmt.Println("RES", check, err)
-			if check {
-				return status, nil
-			}
-			return "", ErrRights
-		}
-	case 2:
-		if status == "Created" || status == "Closed" {
-			check, _ := isUserResponsibleForTender(ctx, db, username, Id)
-			if check {
-				return status, nil
-			}
-			return "", ErrRights
-		}
+		return "", queryError(ctx, err)
 	}
 	return status, nil
 }
 
-func (db *DB) UpdateStatus(ctx context.Context, Id, status, username string, key int) (interface{}, error) {
+func (db *DB) UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
-		return "", ErrNoUser
+		return models.Bid{}, ErrNoUser
+	}
+	BidExist, _ := GetBid(ctx, db, bidID)
+	if !BidExist {
+		return models.Bid{}, ErrNoBid
+	}
+	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidID)
+	if !check {
+		return models.Bid{}, ErrRights
 	}
-	switch key {
-	case 1:
-		BidExist, _ := GetBid(ctx, db, Id)
-		if !BidExist {
-			return "", ErrNoBid
-		}
-		check, _ := isUserResponsibleToUpdateBid(ctx, db, username, Id)
-		if !check {
-			return nil, ErrRights
-		}
-
-		queryHistory := squirrel.Insert("bid_history").
-			Columns("id", "bid_id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
-			Select(
-				squirrel.Select("uuid_generate_v4()", "id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
-					From("bid").
-					Where(squirrel.Eq{"id": Id}),
-			).
-			PlaceholderFormat(squirrel.Dollar)
-
-		sqlHistoryQuery, historyArgs, err := queryHistory.ToSql()
-		if err != nil {
-			return nil, err
-		}
 
-		_, err = db.DB.ExecContext(ctx, sqlHistoryQuery, historyArgs...)
+	err := db.withTx(ctx, func(tx *Tx) error {
+		version, err := lockBid(ctx, tx, bidID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		query := squirrel.Update("bid").
-			Set("status", status).
-			Set("version", squirrel.Expr("version + 1")).
-			Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
-			Where(squirrel.Eq{"id": Id}).
-			PlaceholderFormat(squirrel.Dollar)
-
-		sql, args, err := query.ToSql()
+		current, err := lockedStatus(ctx, tx, "bid", bidID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		_, err = db.DB.ExecContext(ctx, sql, args...)
-		if err != nil {
-			return nil, err
+		if !models.CanTransitionBid(current, status) {
+			return ErrInvalidTransition
 		}
-		updatedBid := BidByID(ctx, db, Id)
-		return updatedBid, nil
-	case 2:
-		TenderExist, _ := GetTender(ctx, db, Id)
-		if !TenderExist {
-			return "", ErrNoTender
+		if err = archiveBid(ctx, tx, bidID); err != nil {
+			return err
 		}
-		check, _ := isUserResponsibleForTender(ctx, db, username, Id)
-		if !check {
-			return nil, ErrRights
+		if err = updateBid(ctx, tx, bidID, version, map[string]interface{}{"status": status}); err != nil {
+			return err
 		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditBid, EntityID: bidID, VersionBefore: version, VersionAfter: version + 1})
+	})
+	if err != nil {
+		return models.Bid{}, err
+	}
+	return BidByID(ctx, db, bidID), nil
+}
 
-		queryHistory := squirrel.Insert("tender_history").
-			Columns("id", "tender_id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
-			Select(
-				squirrel.Select("uuid_generate_v4()", "id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
-					From("tender").
-					Where(squirrel.Eq{"id": Id}),
-			).
-			PlaceholderFormat(squirrel.Dollar)
+func (db *DB) UpdateTenderStatus(ctx context.Context, tenderID, status, username string) (models.Tender, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.Tender{}, ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return models.Tender{}, ErrNoTender
+	}
+	check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+	if !check {
+		return models.Tender{}, ErrRights
+	}
 
-		sqlHistoryQuery, historyArgs, err := queryHistory.ToSql()
+	err := db.withTx(ctx, func(tx *Tx) error {
+		version, err := lockTender(ctx, tx, tenderID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		_, err = db.DB.ExecContext(ctx, sqlHistoryQuery, historyArgs...)
+		current, err := lockedStatus(ctx, tx, "tender", tenderID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		query := squirrel.Update("tender").
-			Set("status", status).
-			Set("version", squirrel.Expr("version + 1")).
-			Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
-			Where(squirrel.Eq{"id": Id}).
-			PlaceholderFormat(squirrel.Dollar)
-
-		sql, args, err := query.ToSql()
-		if err != nil {
-			return nil, err
+		if !models.CanTransitionTender(current, status) {
+			return ErrInvalidTransition
 		}
-
-		_, err = db.DB.ExecContext(ctx, sql, args...)
-		if err != nil {
-			return nil, err
+		if err = archiveTender(ctx, tx, tenderID); err != nil {
+			return err
 		}
-		updatedTender := TenderByID(ctx, db, Id)
-		return updatedTender, nil
+		if err = updateTender(ctx, tx, tenderID, version, map[string]interface{}{"status": status}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: tenderID, VersionBefore: version, VersionAfter: version + 1})
+	})
+	if err != nil {
+		return models.Tender{}, err
 	}
-	return nil, nil
+	return TenderByID(ctx, db, tenderID), nil
 }
 
-func (db *DB) RollbackVersion(ctx context.Context, Id, version, username string, key int) (interface{}, error) {
+func (db *DB) RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
-		return "", ErrNoUser
+		return models.Bid{}, ErrNoUser
+	}
+	BidExist, _ := GetBid(ctx, db, bidID)
+	if !BidExist {
+		return models.Bid{}, ErrNoBid
+	}
+	VersionExist := historyVersionExists(ctx, db, "bid_history", "bid_id", bidID, version)
+	if !VersionExist {
+		return models.Bid{}, ErrNoVersion
+	}
+	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidID)
+	if !check {
+		return models.Bid{}, ErrRights
 	}
-	switch key {
-	case 1:
-		BidExist, _ := GetBid(ctx, db, Id)
-		if !BidExist {
-			return "", ErrNoBid
-		}
-		VersionExist := GetVersion(ctx, db, Id, version, 1)
-		if !VersionExist {
-			return nil, ErrNoVersion
-		}
-		check, _ := isUserResponsibleToUpdateBid(ctx, db, username, Id)
-		if !check {
-			return nil, ErrRights
-		}
 
-		var bid models.Bid
+	err := db.withTx(ctx, func(tx *Tx) error {
+		current, err := lockBid(ctx, tx, bidID)
+		if err != nil {
+			return err
+		}
 
-		query := squirrel.Select("id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
+		query := squirrel.Select("name", "description", "status").
 			From("bid_history").
-			Where(squirrel.Eq{"bid_id": Id, "version": version}).
+			Where(squirrel.Eq{"bid_id": bidID, "version": version}).
 			PlaceholderFormat(squirrel.Dollar)
 
-		sql, args, err := query.ToSql()
+		sqlQuery, args, err := query.ToSql()
 		if err != nil {
-			return nil, nil
+			return err
 		}
 
-		err = db.DB.QueryRowContext(ctx, sql, args...).Scan(
-			&bid.ID,
-			&bid.Name,
-			&bid.Description,
-			&bid.Status,
-			&bid.TenderID,
-			&bid.AuthorType,
-			&bid.AuthorID,
-			&bid.Version,
-			&bid.CreatedAt,
-			&bid.UpdatedAt,
-		)
+		var bid models.Bid
+		err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&bid.Name, &bid.Description, &bid.Status)
 		if err != nil {
-			return nil, err
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNoVersion
+			}
+			return queryError(ctx, err)
+		}
+		// откат не должен обходить переходы статусов: старый статус восстанавливается, только если в него можно перейти
+		status, err := lockedStatus(ctx, tx, "bid", bidID)
+		if err != nil {
+			return err
+		}
+		if bid.Status != status && !models.CanTransitionBid(status, bid.Status) {
+			return ErrInvalidTransition
 		}
 
-		updatedBid, _ := db.EditBid(ctx, Id, username, bid.Name, bid.Description, bid.Status)
-		return updatedBid, nil
-	case 2:
-		TenderExist, err := GetTender(ctx, db, Id)
-		if !TenderExist {
-			fmt.Println(err)
-			return "", ErrNoTender
+		if err = archiveBid(ctx, tx, bidID); err != nil {
+			return err
 		}
-		VersionExist := GetVersion(ctx, db, Id, version, 2)
-		if !VersionExist {
-			return nil, ErrNoVersion
+		if err = updateBid(ctx, tx, bidID, current, map[string]interface{}{
+			"name":        bid.Name,
+			"description": bid.Description,
+			"status":      bid.Status,
+		}); err != nil {
+			return err
 		}
-		check, _ := isUserResponsibleForTender(ctx, db, username, Id)
-		if !check {
-			return nil, ErrRights
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditRollback, EntityType: models.AuditBid, EntityID: bidID, VersionBefore: current, VersionAfter: current + 1})
+	})
+	if err != nil {
+		return models.Bid{}, err
+	}
+	return BidByID(ctx, db, bidID), nil
+}
+
+func (db *DB) RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.Tender{}, ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return models.Tender{}, ErrNoTender
+	}
+	VersionExist := historyVersionExists(ctx, db, "tender_history", "tender_id", tenderID, version)
+	if !VersionExist {
+		return models.Tender{}, ErrNoVersion
+	}
+	check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+	if !check {
+		return models.Tender{}, ErrRights
+	}
+
+	err := db.withTx(ctx, func(tx *Tx) error {
+		current, err := lockTender(ctx, tx, tenderID)
+		if err != nil {
+			return err
 		}
-		var tender models.Tender
 
-		query := squirrel.Select("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
+		query := squirrel.Select("name", "description", "service_type", "status", "submission_deadline", "decision_deadline").
 			From("tender_history").
-			Where(squirrel.Eq{"tender_id": Id, "version": version}).
+			Where(squirrel.Eq{"tender_id": tenderID, "version": version}).
 			PlaceholderFormat(squirrel.Dollar)
 
-		sql, args, err := query.ToSql()
+		sqlQuery, args, err := query.ToSql()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = db.DB.QueryRowContext(ctx, sql, args...).Scan(
-			&tender.ID,
-			&tender.Name,
-			&tender.Description,
-			&tender.ServiceType,
-			&tender.Status,
-			&tender.OrganizationID,
-			&tender.Version,
-			&tender.CreatedAt,
-			&tender.UpdatedAt,
-		)
+		var tender models.Tender
+		err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&tender.Name, &tender.Description, &tender.ServiceType, &tender.Status, &tender.SubmissionDeadline, &tender.DecisionDeadline)
 		if err != nil {
-			return nil, err
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNoVersion
+			}
+			return queryError(ctx, err)
+		}
+		status, err := lockedStatus(ctx, tx, "tender", tenderID)
+		if err != nil {
+			return err
+		}
+		if tender.Status != status && !models.CanTransitionTender(status, tender.Status) {
+			return ErrInvalidTransition
 		}
 
-		updatedTender, _ := db.EditTender(ctx, Id, username, tender.Name, tender.Description, tender.ServiceType, tender.Status)
-
-		return updatedTender, nil
+		if err = archiveTender(ctx, tx, tenderID); err != nil {
+			return err
+		}
+		if err = updateTender(ctx, tx, tenderID, current, map[string]interface{}{
+			"name":                tender.Name,
+			"description":         tender.Description,
+			"service_type":        tender.ServiceType,
+			"status":              tender.Status,
+			"submission_deadline": tender.SubmissionDeadline,
+			"decision_deadline":   tender.DecisionDeadline,
+		}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditRollback, EntityType: models.AuditTender, EntityID: tenderID, VersionBefore: current, VersionAfter: current + 1})
+	})
+	if err != nil {
+		return models.Tender{}, err
 	}
-	return nil, nil
+	return TenderByID(ctx, db, tenderID), nil
 }
 
-func (db *DB) GetTenders(ctx context.Context, limit, offset int, serviceType []string) ([]models.Tender, error) {
+// escapedDescription экранирует описание так же, как html.EscapeString: сниппет - HTML,
+// и разметка из описания не должна попасть в него рядом с <b></b> от ts_headline
+const escapedDescription = `replace(replace(replace(replace(replace(description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+
+// GetTenders возвращает тендеры по фильтру. Анонимно видны только опубликованные тендеры,
+// черновики и закрытые - только ответственным за организацию тендера.
+// С filter.Query выдача ищется по search_vector и упорядочена по ts_rank,
+// а в Snippet попадает HTML-фрагмент экранированного описания с подсветкой совпадений.
+func (db *DB) GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error) {
 	var tenders []models.Tender
 
-	query := squirrel.Select("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
+	statuses := filter.Status
+	if len(statuses) == 0 {
+		statuses = []string{"Published"}
+	}
+
+	query := squirrel.Select("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at", "submission_deadline", "decision_deadline").
 		From("tender").
-		Where(squirrel.Eq{"status": "Published"}).
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		Where(squirrel.Eq{"status": statuses}).
 		PlaceholderFormat(squirrel.Dollar)
 
-	if len(serviceType) > 0 {
-		query = query.Where(squirrel.Eq{"service_type": serviceType})
+	visible := squirrel.Or{squirrel.Eq{"status": "Published"}}
+	if filter.Username != "" {
+		visible = append(visible, squirrel.Expr(
+			`organization_id IN (SELECT "or".organization_id FROM organization_responsible "or" JOIN employee e ON "or".user_id = e.id WHERE e.username = ?)`,
+			filter.Username,
+		))
 	}
+	query = query.Where(visible)
 
-	sql, args, err := query.ToSql()
+	if len(filter.ServiceType) > 0 {
+		query = query.Where(squirrel.Eq{"service_type": filter.ServiceType})
+	}
+	if filter.OrganizationID != "" {
+		query = query.Where(squirrel.Eq{"organization_id": filter.OrganizationID})
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"created_at": filter.CreatedFrom})
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where(squirrel.LtOrEq{"created_at": filter.CreatedTo})
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"updated_at": filter.UpdatedFrom})
+	}
+	if !filter.UpdatedTo.IsZero() {
+		query = query.Where(squirrel.LtOrEq{"updated_at": filter.UpdatedTo})
+	}
+
+	if filter.Query != "" {
+		query = query.
+			Column(squirrel.Alias(squirrel.Expr("ts_rank(search_vector, websearch_to_tsquery('simple', ?))", filter.Query), "rank")).
+			Column(squirrel.Expr("ts_headline('simple', "+escapedDescription+", websearch_to_tsquery('simple', ?), 'StartSel=<b>, StopSel=</b>, MaxFragments=2')", filter.Query)).
+			Where("search_vector @@ websearch_to_tsquery('simple', ?)", filter.Query)
+	} else {
+		query = query.
+			Column("0::float8").
+			Column("''")
+	}
+	query, err := paginate(query, tenderOrder(filter), models.Page{Limit: filter.Limit, Offset: filter.Offset, After: filter.After})
 	if err != nil {
 		return nil, err
 	}
-	rows, err := db.DB.QueryContext(ctx, sql, args...)
+
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
+	rows, err := db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var tender models.Tender
 		if err = rows.Scan(
@@ -507,19 +666,44 @@ func (db *DB) GetTenders(ctx context.Context, limit, offset int, serviceType []s
 			&tender.OrganizationID,
 			&tender.Version,
 			&tender.CreatedAt,
-			&tender.UpdatedAt); err != nil {
+			&tender.UpdatedAt,
+			&tender.SubmissionDeadline,
+			&tender.DecisionDeadline,
+			&tender.Rank,
+			&tender.Snippet); err != nil {
 			return nil, err
 		}
 		tenders = append(tenders, tender)
 	}
-	defer rows.Close()
-	sort.Slice(tenders, func(i, j int) bool {
-		return tenders[i].Name < tenders[j].Name
-	})
-	return tenders, nil
+	return tenders, rows.Err()
+}
+
+// tenderOrder переводит сортировку фильтра в колонки ORDER BY; id в конце делает порядок однозначным.
+// Колонки совпадают по порядку с ключом models.TenderFilter.Key.
+func tenderOrder(filter models.TenderFilter) []keyColumn {
+	id := keyColumn{name: "id"}
+	switch filter.Sort {
+	case models.TenderSortCreatedAt:
+		return []keyColumn{{name: "created_at", kind: keyTime, desc: filter.Desc}, id}
+	case models.TenderSortUpdatedAt:
+		return []keyColumn{{name: "updated_at", kind: keyTime, desc: filter.Desc}, id}
+	case models.TenderSortName:
+		return []keyColumn{{name: "name", desc: filter.Desc}, id}
+	}
+	if filter.Query != "" {
+		rank := keyColumn{
+			name: "rank",
+			expr: "ts_rank(search_vector, websearch_to_tsquery('simple', ?))",
+			args: []interface{}{filter.Query},
+			kind: keyFloat,
+			desc: true,
+		}
+		return []keyColumn{rank, {name: "name"}, id}
+	}
+	return []keyColumn{{name: "name", desc: filter.Desc}, id}
 }
 
-func (db *DB) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string) (models.Tender, error) {
+func (db *DB) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
 		return models.Tender{}, ErrNoUser
@@ -533,49 +717,45 @@ func (db *DB) EditTender(ctx context.Context, tenderId, username, tenderName, de
 		return models.Tender{}, ErrRights
 	}
 
-	queryHistory := squirrel.Insert("tender_history").
-		Columns("id", "tender_id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
-		Select(
-			squirrel.Select("uuid_generate_v4()", "id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
-				From("tender").
-				Where(squirrel.Eq{"id": tenderId}),
-		).
-		PlaceholderFormat(squirrel.Dollar)
-
-	sqlHistoryQuery, historyArgs, err := queryHistory.ToSql()
-	if err != nil {
-		return models.Tender{}, err
-	}
-
-	_, err = db.DB.ExecContext(ctx, sqlHistoryQuery, historyArgs...)
-	if err != nil {
-		return models.Tender{}, err
-	}
-	query := squirrel.Update("tender").
-		Set("version", squirrel.Expr("version + 1")).
-		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
-		Where(squirrel.Eq{"id": tenderId}).
-		PlaceholderFormat(squirrel.Dollar)
-
+	fields := map[string]interface{}{}
 	if status != "" {
-		query = query.Set("status", status)
+		fields["status"] = status
 	}
 	if tenderName != "" {
-		query = query.Set("name", tenderName)
+		fields["name"] = tenderName
 	}
 	if description != "" {
-		query = query.Set("description", description)
+		fields["description"] = description
 	}
 	if serviceType != "" {
-		query = query.Set("service_type", serviceType)
-	}
-
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return models.Tender{}, err
+		fields["service_type"] = serviceType
 	}
 
-	_, err = db.DB.ExecContext(ctx, sql, args...)
+	err := db.withTx(ctx, func(tx *Tx) error {
+		version, err := lockTender(ctx, tx, tenderId)
+		if err != nil {
+			return err
+		}
+		if expectedVersion != 0 && version != expectedVersion {
+			return ErrVersionMismatch
+		}
+		if status != "" {
+			current, err := lockedStatus(ctx, tx, "tender", tenderId)
+			if err != nil {
+				return err
+			}
+			if !models.CanTransitionTender(current, status) {
+				return ErrInvalidTransition
+			}
+		}
+		if err = archiveTender(ctx, tx, tenderId); err != nil {
+			return err
+		}
+		if err = updateTender(ctx, tx, tenderId, version, fields); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditEdit, EntityType: models.AuditTender, EntityID: tenderId, VersionBefore: version, VersionAfter: version + 1})
+	})
 	if err != nil {
 		return models.Tender{}, err
 	}
@@ -583,7 +763,7 @@ func (db *DB) EditTender(ctx context.Context, tenderId, username, tenderName, de
 	return updatedTender, nil
 }
 
-func (db *DB) EditBid(ctx context.Context, bidId, username, bidName, description, status string) (models.Bid, error) {
+func (db *DB) EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
 		return models.Bid{}, ErrNoUser
@@ -592,49 +772,47 @@ func (db *DB) EditBid(ctx context.Context, bidId, username, bidName, description
 	if !BidExist {
 		return models.Bid{}, ErrNoBid
 	}
-	check, err := isUserResponsibleToUpdateBid(ctx, db, username, bidId)
+	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidId)
 	if !check {
 		return models.Bid{}, ErrRights
 	}
 
-	queryHistory := squirrel.Insert("bid_history").
-		Columns("id", "bid_id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
-		Select(
-			squirrel.Select("uuid_generate_v4()", "id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
-				From("bid").
-				Where(squirrel.Eq{"id": bidId}),
-		).
-		PlaceholderFormat(squirrel.Dollar)
-	sqlHistoryQuery, historyArgs, err := queryHistory.ToSql()
-	if err != nil {
-		return models.Bid{}, err
-	}
-	_, err = db.DB.ExecContext(ctx, sqlHistoryQuery, historyArgs...)
-	if err != nil {
-		return models.Bid{}, err
-	}
-
-	query := squirrel.Update("bid").
-		Set("version", squirrel.Expr("version + 1")).
-		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
-		Where(squirrel.Eq{"id": bidId}).
-		PlaceholderFormat(squirrel.Dollar)
-
+	fields := map[string]interface{}{}
 	if status != "" {
-		query = query.Set("status", status)
+		fields["status"] = status
 	}
 	if bidName != "" {
-		query = query.Set("name", bidName)
+		fields["name"] = bidName
 	}
 	if description != "" {
-		query = query.Set("description", description)
+		fields["description"] = description
 	}
 
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return models.Bid{}, err
-	}
-	_, err = db.DB.ExecContext(ctx, sql, args...)
+	err := db.withTx(ctx, func(tx *Tx) error {
+		version, err := lockBid(ctx, tx, bidId)
+		if err != nil {
+			return err
+		}
+		if expectedVersion != 0 && version != expectedVersion {
+			return ErrVersionMismatch
+		}
+		if status != "" {
+			current, err := lockedStatus(ctx, tx, "bid", bidId)
+			if err != nil {
+				return err
+			}
+			if !models.CanTransitionBid(current, status) {
+				return ErrInvalidTransition
+			}
+		}
+		if err = archiveBid(ctx, tx, bidId); err != nil {
+			return err
+		}
+		if err = updateBid(ctx, tx, bidId, version, fields); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditEdit, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: version, VersionAfter: version + 1})
+	})
 	if err != nil {
 		return models.Bid{}, err
 	}
@@ -642,7 +820,7 @@ func (db *DB) EditBid(ctx context.Context, bidId, username, bidName, description
 	return updatedBid, nil
 }
 
-func (db *DB) SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) { // TODO: необходима еще таблица вида bidID - decision
+func (db *DB) SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
 		return models.Bid{}, ErrNoUser
@@ -658,60 +836,132 @@ func (db *DB) SubmitDecisionBid(ctx context.Context, bidId string, decision stri
 		return models.Bid{}, ErrRights
 	}
 
-	query := squirrel.Insert("decisions").
-		Columns("id", "bid_id", "decision", "created_at", "created_by").
-		Values(uuid.GenerateCorrelationID(), bidId, decision, time.Now(), username).
-		PlaceholderFormat(squirrel.Dollar)
+	err := db.withTx(ctx, func(tx *Tx) error {
+		version, err := lockBid(ctx, tx, bidId)
+		if err != nil {
+			return err
+		}
+		tenderVersion, err := lockTender(ctx, tx, tenderId)
+		if err != nil {
+			return err
+		}
 
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return models.Bid{}, err
-	}
+		// статусы перечитываются под блокировкой, чтобы параллельные голоса не приняли решение дважды
+		status, err := lockedStatus(ctx, tx, "bid", bidId)
+		if err != nil {
+			return err
+		}
+		if status == "Approved" || status == "Rejected" {
+			return ErrDecisionMade
+		}
+		// решение - тоже переход статуса: голосовать можно только по опубликованному предложению опубликованного тендера
+		if !models.CanTransitionBid(status, decision) {
+			return ErrInvalidTransition
+		}
+		tenderStatus, err := lockedStatus(ctx, tx, "tender", tenderId)
+		if err != nil {
+			return err
+		}
+		if tenderStatus != "Published" {
+			return ErrInvalidTransition
+		}
 
-	_, err = db.DB.ExecContext(ctx, sql, args...)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("error executing query: %w", err)
-	}
+		voted, err := hasUserDecided(ctx, tx, bidId, username)
+		if err != nil {
+			return err
+		}
+		if voted {
+			return ErrDecisionMade
+		}
+
+		query := squirrel.Insert("decisions").
+			Columns("id", "bid_id", "decision", "created_at", "created_by").
+			Values(uuid.GenerateCorrelationID(), bidId, decision, time.Now(), username).
+			PlaceholderFormat(squirrel.Dollar)
+
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return queryError(ctx, err)
+		}
+
+		// Одного отказа достаточно, чтобы отклонить предложение
+		if decision == "Rejected" {
+			if err = archiveBid(ctx, tx, bidId); err != nil {
+				return err
+			}
+			if err = updateBid(ctx, tx, bidId, version, map[string]interface{}{"status": "Rejected"}); err != nil {
+				return err
+			}
+			return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: version, VersionAfter: version + 1})
+		}
+
+		// Для согласования нужен кворум: min(3, количество ответственных за организацию)
+		approvals, err := countDecisions(ctx, tx, bidId, "Approved")
+		if err != nil {
+			return err
+		}
+		quorum, err := decisionQuorum(ctx, tx, tenderId)
+		if err != nil {
+			return err
+		}
+		if approvals < quorum {
+			// Голос учтён, но статус предложения не изменился
+			return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: version, VersionAfter: version})
+		}
+
+		if err = archiveBid(ctx, tx, bidId); err != nil {
+			return err
+		}
+		if err = updateBid(ctx, tx, bidId, version, map[string]interface{}{"status": "Approved"}); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditDecision, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: version, VersionAfter: version + 1})
+		if err != nil {
+			return err
+		}
 
-	if decision == "Approved" {
-		_, _ = db.UpdateStatus(ctx, tenderId, "Closed", username, 2)
+		if err = archiveTender(ctx, tx, tenderId); err != nil {
+			return err
+		}
+		if err = updateTender(ctx, tx, tenderId, tenderVersion, map[string]interface{}{"status": "Closed"}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditStatus, EntityType: models.AuditTender, EntityID: tenderId, VersionBefore: tenderVersion, VersionAfter: tenderVersion + 1})
+	})
+	if err != nil {
+		return models.Bid{}, err
 	}
-	return bid, nil
+	return BidByID(ctx, db, bidId), nil
 }
 
-func (db *DB) GetTenderBids(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Bid, error) {
-	userExist, _ := GetUser(ctx, db, username)
-	if !userExist {
-		return []models.Bid{}, ErrNoUser
-	}
-	BidExist, _ := GetTender(ctx, db, tenderID)
-	if !BidExist {
-		return []models.Bid{}, ErrNoTender
-	}
-	status, _ := db.GetStatus(ctx, tenderID, username, 2)
-	fmt.Println("STATUS", status)
-	if status != "Published" {
-		check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
-		if !check {
-			return []models.Bid{}, ErrRights
-		}
+func (db *DB) GetTenderBids(ctx context.Context, tenderID, username string, page models.Page) ([]models.Bid, error) {
+	if err := tenderBidsVisible(ctx, db, tenderID, username); err != nil {
+		return []models.Bid{}, err
 	}
 
 	query := squirrel.Select("id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
 		From("bid").
 		Where(squirrel.Eq{"tender_id": tenderID}).
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		Where(bidVisible(username)).
 		PlaceholderFormat(squirrel.Dollar)
+	query, err := paginate(query, []keyColumn{{name: "name"}, {name: "id"}}, page)
+	if err != nil {
+		return nil, err
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	rows, err := db.DB.QueryContext(ctx, sqlQuery, args...)
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, queryError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -731,20 +981,38 @@ func (db *DB) GetTenderBids(ctx context.Context, tenderID, username string, limi
 			&bid.UpdatedAt); err != nil {
 			return nil, err
 		}
-		check, _ := isUserResponsibleForBid(ctx, db, username, bid.ID)
-		if check {
-			bids = append(bids, bid)
-		}
+		bids = append(bids, bid)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
 	}
 	if len(bids) == 0 {
 		return bids, ErrNoBid
 	}
-	sort.Slice(bids, func(i, j int) bool {
-		return bids[i].Name < bids[j].Name
-	})
 	return bids, nil
 }
 
+// tenderBidsVisible - кто видит предложения тендера и приложенные к нему файлы:
+// существующий сотрудник, если тендер опубликован или сотрудник отвечает за его организацию
+func tenderBidsVisible(ctx context.Context, db *DB, tenderID, username string) error {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return ErrNoTender
+	}
+	status, _ := db.GetTenderStatus(ctx, tenderID, username)
+	if status != "Published" {
+		check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+		if !check {
+			return ErrRights
+		}
+	}
+	return nil
+}
+
 func (db *DB) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) {
 	userExist, _ := GetUser(ctx, db, username)
 	if !userExist {
@@ -762,30 +1030,34 @@ func (db *DB) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback stri
 	}
 
 	var reviewer string
-	sql := "SELECT id FROM employee WHERE username = $1"
-	_ = db.DB.QueryRowContext(ctx, sql, username).Scan(&reviewer)
-
-	fmt.Println(reviewer)
+	sqlQuery := "SELECT id FROM employee WHERE username = $1"
+	_ = db.QueryRowContext(ctx, sqlQuery, username).Scan(&reviewer)
 
 	query := squirrel.Insert("bid_reviews").
 		Columns("id", "bid_id", "review", "reviewer", "created_at", "bid_author_id").
 		Values(uuid.GenerateCorrelationID(), bidId, bidFeedback, reviewer, time.Now(), bid.AuthorID).
 		PlaceholderFormat(squirrel.Dollar)
 
-	sql, args, err := query.ToSql()
+	sqlQuery, args, err := query.ToSql()
 	if err != nil {
 		return models.Bid{}, err
 	}
 
-	_, err = db.DB.ExecContext(ctx, sql, args...)
+	err = db.withTx(ctx, func(tx *Tx) error {
+		if _, err := tx.ExecContext(ctx, sqlQuery, args...); err != nil {
+			return queryError(ctx, err)
+		}
+		// Отзыв не меняет версию предложения, поэтому версии до и после совпадают
+		return recordAudit(ctx, tx, models.AuditEvent{Actor: username, Action: models.AuditFeedback, EntityType: models.AuditBid, EntityID: bidId, VersionBefore: int(bid.Version), VersionAfter: int(bid.Version)})
+	})
 	if err != nil {
-		return models.Bid{}, fmt.Errorf("error executing query: %w", err)
+		return models.Bid{}, err
 	}
 
 	return bid, nil
 }
 
-func (db *DB) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, limit, offset int) ([]models.FeedBack, error) {
+func (db *DB) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, page models.Page) ([]models.FeedBack, error) {
 	authorExist, _ := GetUser(ctx, db, authorUsername)
 	if !authorExist {
 		return nil, ErrNoUser
@@ -803,8 +1075,7 @@ func (db *DB) GetFeedback(ctx context.Context, tenderId, authorUsername, request
 		return nil, ErrRights
 	}
 
-	requesterBids, _ := db.GetMy(ctx, math.MaxInt32, 0, requesterUsername, 1)
-	bids, _ := requesterBids.([]models.Bid)
+	bids, _ := db.GetMyBids(ctx, models.Page{Limit: math.MaxInt32}, requesterUsername)
 
 	ok := false
 	for _, bid := range bids {
@@ -820,20 +1091,22 @@ func (db *DB) GetFeedback(ctx context.Context, tenderId, authorUsername, request
 
 	query := squirrel.Select("br.id", "br.review", "br.created_at").
 		From("bid_reviews br").
-		Join("employee e ON br.bid_author_id = e.id").
-		Where(squirrel.Eq{"e.username": requesterUsername}).
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		Join("bid ON br.bid_id = bid.id").
+		Where(bidAuthoredBy(requesterUsername)).
 		PlaceholderFormat(squirrel.Dollar)
+	query, err := paginate(query, []keyColumn{{name: "br.created_at", kind: keyTime}, {name: "br.id"}}, page)
+	if err != nil {
+		return nil, err
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("error building query: %v", err)
 	}
 
-	rows, err := db.DB.QueryContext(ctx, sql, args...)
+	rows, err := db.QueryContext(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error executing query: %v", err)
+		return nil, queryError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -844,8 +1117,7 @@ func (db *DB) GetFeedback(ctx context.Context, tenderId, authorUsername, request
 		}
 		reviews = append(reviews, review)
 	}
-
-	return reviews, nil
+	return reviews, rows.Err()
 }
 
 func IsUserResponsibleForOrganization(ctx context.Context, db *DB, userName, organizationID string) (bool, error) {
@@ -860,7 +1132,7 @@ func IsUserResponsibleForOrganization(ctx context.Context, db *DB, userName, org
 	}
 
 	var userID string
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&userID)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&userID)
 	if err != nil {
 		return false, err
 	}
@@ -876,20 +1148,23 @@ func IsUserResponsibleForOrganization(ctx context.Context, db *DB, userName, org
 	}
 
 	var realID string
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&realID)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&realID)
 	if err != nil {
-		fmt.Println(err)
 		return false, err
 	}
 
 	return realID == userID, nil
 }
 
+// isUserResponsibleForTender - пользователь отвечает за организацию тендера; прочие его организации не учитываются
 func isUserResponsibleForTender(ctx context.Context, db *DB, username, tenderID string) (bool, error) {
-	var TenderOrganizationID string
-	query := squirrel.Select("organization_id").
+	query := squirrel.Select("1").
 		From("tender").
-		Where(squirrel.Eq{"id": tenderID}).
+		Join(`organization_responsible "or" ON "or".organization_id = tender.organization_id`).
+		Join(`employee e ON "or".user_id = e.id`).
+		Where(squirrel.Eq{"tender.id": tenderID, "e.username": username}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
 		PlaceholderFormat(squirrel.Dollar)
 
 	sql, args, err := query.ToSql()
@@ -897,39 +1172,26 @@ func isUserResponsibleForTender(ctx context.Context, db *DB, username, tenderID
 		return false, err
 	}
 
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&TenderOrganizationID)
-	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
-	}
-
-	var UserOrganizationID string
-	query = squirrel.Select(`"or".organization_id`).
-		From(`organization_responsible "or"`).
-		Join("employee e ON \"or\".user_id = e.id").
-		Where(squirrel.Eq{"e.username": username}).
-		PlaceholderFormat(squirrel.Dollar)
-
-	sql, args, err = query.ToSql()
-	if err != nil {
-		return false, err
-	}
-
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&UserOrganizationID)
+	var responsible bool
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&responsible)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
-	return UserOrganizationID == TenderOrganizationID, nil
+	return responsible, nil
 }
 
+// isUserResponsibleToUpdateBid - пользователь отвечает за организацию-автора предложения
+// или за ту же организацию, что и сотрудник-автор
 func isUserResponsibleToUpdateBid(ctx context.Context, db *DB, username, bidID string) (bool, error) {
 	query := squirrel.Select("1").
 		From("bid").
-		Join(`organization_responsible "or_author" ON bid.author_id = "or_author".user_id`).
-		Join(`organization_responsible "or_user" ON "or_user".organization_id = "or_author".organization_id`).
+		Join(`organization_responsible "or_user" ON (bid.author_type = 'Organization' AND "or_user".organization_id = bid.author_id)
+			OR (bid.author_type = 'User' AND "or_user".organization_id IN (SELECT organization_id FROM organization_responsible WHERE user_id = bid.author_id))`).
 		Join(`employee e ON "or_user".user_id = e.id`).
 		Where(squirrel.Eq{"e.username": username}).
 		Where(squirrel.Eq{"bid.id": bidID}).
+		Limit(1).
 		PlaceholderFormat(squirrel.Dollar)
 
 	sql, args, err := query.ToSql()
@@ -938,19 +1200,22 @@ func isUserResponsibleToUpdateBid(ctx context.Context, db *DB, username, bidID s
 	}
 
 	var author bool
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&author)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&author)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
 	return author, nil
 }
 
+// isUserResponsibleForBid - пользователь видит предложение: он автор, отвечает за организацию автора или за организацию тендера
 func isUserResponsibleForBid(ctx context.Context, db *DB, username, bidID string) (bool, error) {
-	query := squirrel.Select("bid.tender_id", "employee.username").
+	query := squirrel.Select("1").
 		From("bid").
-		Join("employee ON bid.author_id = employee.id").
 		Where(squirrel.Eq{"bid.id": bidID}).
+		Where(bidVisible(username)).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
 		PlaceholderFormat(squirrel.Dollar)
 
 	sql, args, err := query.ToSql()
@@ -958,24 +1223,33 @@ func isUserResponsibleForBid(ctx context.Context, db *DB, username, bidID string
 		return false, err
 	}
 
-	var tenderID, author string
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&tenderID, &author)
+	var visible bool
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&visible)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
+	return visible, nil
+}
 
-	if author == username {
-		return true, nil
-	}
-	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidID)
-	if check {
-		return true, nil
-	}
-	ok, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
-	if ok {
-		return true, nil
-	}
-	return false, nil
+// bidVisible - условие isUserResponsibleForBid для выборок из bid. Проверяется в самом запросе,
+// чтобы LIMIT и курсор страницы считали только видимые сотруднику предложения.
+func bidVisible(username string) squirrel.Sqlizer {
+	return squirrel.Expr(`(bid.author_id IN (SELECT id FROM employee WHERE username = ?)
+		OR EXISTS (SELECT 1 FROM organization_responsible "or" JOIN employee e ON "or".user_id = e.id
+			WHERE e.username = ? AND (
+				"or".organization_id IN (SELECT organization_id FROM tender WHERE tender.id = bid.tender_id)
+				OR (bid.author_type = 'Organization' AND "or".organization_id = bid.author_id)
+				OR (bid.author_type = 'User' AND "or".organization_id IN (SELECT organization_id FROM organization_responsible WHERE user_id = bid.author_id)))))`,
+		username, username)
+}
+
+// bidAuthoredBy - условие для выборок из bid: предложение подал сам сотрудник
+// или организация, за которую он отвечает
+func bidAuthoredBy(username string) squirrel.Sqlizer {
+	return squirrel.Expr(`((bid.author_type = 'User' AND bid.author_id IN (SELECT id FROM employee WHERE username = ?))
+		OR (bid.author_type = 'Organization' AND bid.author_id IN (SELECT "or".organization_id FROM organization_responsible "or"
+			JOIN employee e ON "or".user_id = e.id WHERE e.username = ?)))`,
+		username, username)
 }
 
 func GetBid(ctx context.Context, db *DB, bidID string) (bool, error) {
@@ -990,9 +1264,9 @@ func GetBid(ctx context.Context, db *DB, bidID string) (bool, error) {
 	}
 
 	var count int
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
 	return count > 0, nil
@@ -1010,9 +1284,9 @@ func GetTender(ctx context.Context, db *DB, tenderID string) (bool, error) {
 	}
 
 	var count int
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
 	return count > 0, nil
@@ -1030,27 +1304,18 @@ func GetUser(ctx context.Context, db *DB, userName string) (bool, error) {
 	}
 
 	var count int
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
 	return count > 0, nil
 }
 
-func GetVersion(ctx context.Context, db *DB, id, version string, key int) bool {
-	var tableName, temp string
-	switch key {
-	case 1:
-		tableName = "bid_history"
-		temp = "bid_id"
-	case 2:
-		tableName = "tender_history"
-		temp = "tender_id"
-	}
+func historyVersionExists(ctx context.Context, db *DB, table, idColumn, id string, version int) bool {
 	query := squirrel.Select("COUNT(*)").
-		From(tableName).
-		Where(squirrel.Eq{temp: id}).
+		From(table).
+		Where(squirrel.Eq{idColumn: id}).
 		Where(squirrel.Eq{"version": version}).
 		PlaceholderFormat(squirrel.Dollar)
 
@@ -1060,7 +1325,7 @@ func GetVersion(ctx context.Context, db *DB, id, version string, key int) bool {
 	}
 
 	var count int
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if err != nil {
 		return false
 	}
@@ -1080,9 +1345,9 @@ func GetUserByID(ctx context.Context, db *DB, id string) (bool, error) {
 	}
 
 	var count int
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("error executing query: %w", err)
+		return false, queryError(ctx, err)
 	}
 
 	return count > 0, nil
@@ -1091,7 +1356,7 @@ func GetUserByID(ctx context.Context, db *DB, id string) (bool, error) {
 func TenderByID(ctx context.Context, db *DB, tenderID string) models.Tender {
 	var tender models.Tender
 
-	query := squirrel.Select("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at").
+	query := squirrel.Select("id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at", "submission_deadline", "decision_deadline").
 		From("tender").
 		Where(squirrel.Eq{"id": tenderID}).
 		OrderBy("version DESC").
@@ -1103,7 +1368,7 @@ func TenderByID(ctx context.Context, db *DB, tenderID string) models.Tender {
 		return tender
 	}
 
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(
+	err = db.QueryRowContext(ctx, sql, args...).Scan(
 		&tender.ID,
 		&tender.Name,
 		&tender.Description,
@@ -1113,6 +1378,8 @@ func TenderByID(ctx context.Context, db *DB, tenderID string) models.Tender {
 		&tender.Version,
 		&tender.CreatedAt,
 		&tender.UpdatedAt,
+		&tender.SubmissionDeadline,
+		&tender.DecisionDeadline,
 	)
 	if err != nil {
 		return tender
@@ -1134,7 +1401,7 @@ func BidByID(ctx context.Context, db *DB, bidID string) models.Bid {
 		return bid
 	}
 
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(
+	err = db.QueryRowContext(ctx, sql, args...).Scan(
 		&bid.ID,
 		&bid.Name,
 		&bid.Description,
@@ -1164,10 +1431,104 @@ func GetUsernameByID(ctx context.Context, db *DB, id string) string {
 	}
 
 	var username string
-	err = db.DB.QueryRowContext(ctx, sql, args...).Scan(&username)
+	err = db.QueryRowContext(ctx, sql, args...).Scan(&username)
 	if err != nil {
 		return ""
 	}
 
 	return username
 }
+
+func hasUserDecided(ctx context.Context, tx runner, bidID, username string) (bool, error) {
+	query := squirrel.Select("COUNT(*)").
+		From("decisions").
+		Where(squirrel.Eq{"bid_id": bidID, "created_by": username}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return false, queryError(ctx, err)
+	}
+
+	return count > 0, nil
+}
+
+func countDecisions(ctx context.Context, tx runner, bidID, decision string) (int, error) {
+	query := squirrel.Select("COUNT(DISTINCT created_by)").
+		From("decisions").
+		Where(squirrel.Eq{"bid_id": bidID, "decision": decision}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, queryError(ctx, err)
+	}
+
+	return count, nil
+}
+
+// decisionQuorum возвращает количество согласований, необходимое для принятия предложения по тендеру.
+func decisionQuorum(ctx context.Context, tx runner, tenderID string) (int, error) {
+	query := squirrel.Select(`COUNT("or".id)`).
+		From(`organization_responsible "or"`).
+		Join(`tender t ON t.organization_id = "or".organization_id`).
+		Where(squirrel.Eq{"t.id": tenderID}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var responsible int
+	err = tx.QueryRowContext(ctx, sql, args...).Scan(&responsible)
+	if err != nil {
+		return 0, queryError(ctx, err)
+	}
+
+	return min(3, responsible), nil
+}
+
+// GetUserCredentials возвращает сотрудника и хеш его пароля для выдачи токена
+func (db *DB) GetUserCredentials(ctx context.Context, username string) (models.User, string, error) {
+	query := squirrel.Select("id", "username", "COALESCE(first_name, '')", "COALESCE(last_name, '')", "created_at", "updated_at", "COALESCE(password_hash, '')").
+		From("employee").
+		Where(squirrel.Eq{"username": username}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return models.User{}, "", err
+	}
+
+	var user models.User
+	var passwordHash string
+	err = db.QueryRowContext(ctx, sqlQuery, args...).Scan(
+		&user.ID,
+		&user.Username,
+		&user.FirstName,
+		&user.LastName,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&passwordHash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, "", ErrNoUser
+		}
+		return models.User{}, "", queryError(ctx, err)
+	}
+	return user, passwordHash, nil
+}
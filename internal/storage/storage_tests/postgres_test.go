@@ -0,0 +1,263 @@
+package storage_test
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/pkg/uuid"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// openTestDB подключается к базе из TEST_POSTGRES_CONN (URL postgres://...) и применяет миграции в отдельной схеме,
+// которая удаляется после теста. Без переменной окружения тест пропускается.
+func openTestDB(t *testing.T) *storage.DB {
+	dsn := os.Getenv("TEST_POSTGRES_CONN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	db, err := storage.NewStorage(u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.Migrate(ctx, db.DB, "up"))
+	return db
+}
+
+// addEmployee добавляет сотрудника напрямую в таблицу: API для создания сотрудников нет
+func addEmployee(t *testing.T, db *storage.DB, username string) string {
+	id := uuid.GenerateCorrelationID()
+	_, err := db.DB.ExecContext(context.Background(), "INSERT INTO employee (id, username) VALUES ($1, $2)", id, username)
+	require.NoError(t, err)
+	return id
+}
+
+func TestIsUserResponsibleForTender_TwoOrganizations(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+	addEmployee(t, db, "bob")
+
+	first := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "First"}
+	require.NoError(t, db.CreateOrganization(ctx, first, "alice"))
+	tender := models.Tender{ID: uuid.GenerateCorrelationID(), Name: "Tender", Description: "Desc", ServiceType: "Delivery", Status: "Created", OrganizationID: first.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddTender(ctx, tender, "alice"))
+
+	// Вторая организация не должна отнимать у alice права на тендеры первой
+	second := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Second"}
+	require.NoError(t, db.CreateOrganization(ctx, second, "alice"))
+	require.NoError(t, db.CreateOrganization(ctx, models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Third"}, "bob"))
+
+	for i := 0; i < 5; i++ {
+		edited, err := db.EditTender(ctx, tender.ID, "alice", fmt.Sprintf("Edit %d", i), "", "", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, i+2, edited.Version)
+	}
+
+	_, err := db.EditTender(ctx, tender.ID, "bob", "Stolen", "", "", "", 0)
+	assert.ErrorIs(t, err, storage.ErrRights)
+}
+
+func TestGetTenderBids_PagesCountVisibleBidsOnly(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+	bobID := addEmployee(t, db, "bob")
+	carolID := addEmployee(t, db, "carol")
+
+	organization := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Org"}
+	require.NoError(t, db.CreateOrganization(ctx, organization, "alice"))
+	tender := models.Tender{ID: uuid.GenerateCorrelationID(), Name: "Tender", Description: "Desc", ServiceType: "Delivery", Status: "Published", OrganizationID: organization.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddTender(ctx, tender, "alice"))
+
+	// Предложения bob и carol чередуются по имени, bob видит только свои
+	authors := []string{carolID, bobID, carolID, bobID, carolID}
+	for i, authorID := range authors {
+		bid := models.Bid{ID: uuid.GenerateCorrelationID(), Name: fmt.Sprintf("Bid %d", i), Description: "Desc", Status: "Created", TenderID: tender.ID, AuthorType: "User", AuthorID: authorID, Version: 1, CreatedAt: time.Now()}
+		require.NoError(t, db.AddBid(ctx, bid, authorID))
+	}
+
+	page := models.Page{Limit: 1}
+	var names []string
+	for {
+		bids, err := db.GetTenderBids(ctx, tender.ID, "bob", page)
+		if errors.Is(err, storage.ErrNoBid) {
+			break
+		}
+		require.NoError(t, err)
+		require.Len(t, bids, 1)
+		names = append(names, bids[0].Name)
+		page.After = models.BidKey(bids[0])
+	}
+	assert.Equal(t, []string{"Bid 1", "Bid 3"}, names)
+
+	bids, err := db.GetTenderBids(ctx, tender.ID, "alice", models.Page{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, bids, len(authors))
+}
+
+func TestQueryContext_SpanCoversIteration(t *testing.T) {
+	db := openTestDB(t)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	rows, err := db.QueryContext(context.Background(), "SELECT generate_series(1, 3)")
+	require.NoError(t, err)
+	// Пока строки читаются, span запроса открыт
+	assert.Empty(t, recorder.Ended())
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	require.NoError(t, rows.Close())
+	assert.Equal(t, 3, count)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "db SELECT", spans[0].Name())
+
+	// Повторный Close не закрывает span второй раз
+	require.NoError(t, rows.Close())
+	assert.Len(t, recorder.Ended(), 1)
+}
+
+func TestSchemaVersion_ReadOnly(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	current, latest, err := db.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, current)
+
+	// Без таблицы версий схема считается непримененной, а проба не создаёт таблицу сама
+	_, err = db.DB.ExecContext(ctx, "DROP TABLE goose_db_version")
+	require.NoError(t, err)
+	current, _, err = db.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, current)
+
+	var table sql.NullString
+	require.NoError(t, db.DB.QueryRowContext(ctx, "SELECT to_regclass('goose_db_version')::text").Scan(&table))
+	assert.False(t, table.Valid)
+}
+
+func TestAddOrganizationResponsible_Concurrent(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+	addEmployee(t, db, "bob")
+	organization := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Org"}
+	require.NoError(t, db.CreateOrganization(ctx, organization, "alice"))
+
+	// Оба запроса проходят проверку countResponsible, проигравший упирается в уникальный индекс
+	const attempts = 5
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			errs <- db.AddOrganizationResponsible(ctx, organization.ID, "alice", "bob")
+		}()
+	}
+	added := 0
+	for i := 0; i < attempts; i++ {
+		err := <-errs
+		if err == nil {
+			added++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrAlreadyResponsible)
+	}
+	assert.Equal(t, 1, added)
+}
+
+func TestDeleteOrganization_KeepsAuditEvents(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+	organization := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Org"}
+	require.NoError(t, db.CreateOrganization(ctx, organization, "alice"))
+	tender := models.Tender{ID: uuid.GenerateCorrelationID(), Name: "Tender", Description: "Desc", ServiceType: "Delivery", Status: "Created", OrganizationID: organization.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddTender(ctx, tender, "alice"))
+
+	require.NoError(t, db.DeleteOrganization(ctx, organization.ID, "alice"))
+
+	var events int
+	require.NoError(t, db.DB.QueryRowContext(ctx, "SELECT count(*) FROM audit_events WHERE organization_id = $1", organization.ID).Scan(&events))
+	assert.Equal(t, 1, events)
+}
+
+func TestOrganizationBids_MyBidsAndReviews(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+	bobID := addEmployee(t, db, "bob")
+	addEmployee(t, db, "carol")
+
+	customer := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Customer"}
+	require.NoError(t, db.CreateOrganization(ctx, customer, "alice"))
+	vendor := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Vendor"}
+	require.NoError(t, db.CreateOrganization(ctx, vendor, "bob"))
+	require.NoError(t, db.AddOrganizationResponsible(ctx, vendor.ID, "bob", "carol"))
+	tender := models.Tender{ID: uuid.GenerateCorrelationID(), Name: "Tender", Description: "Desc", ServiceType: "Delivery", Status: "Published", OrganizationID: customer.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddTender(ctx, tender, "alice"))
+
+	// Предложение от организации подаёт bob, а в своих его видит и второй ответственный carol
+	bid := models.Bid{ID: uuid.GenerateCorrelationID(), Name: "Vendor bid", Description: "Desc", Status: "Published", TenderID: tender.ID, AuthorType: "Organization", AuthorID: vendor.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddBid(ctx, bid, bobID))
+	bids, err := db.GetMyBids(ctx, models.Page{Limit: 10}, "carol")
+	require.NoError(t, err)
+	require.Len(t, bids, 1)
+	assert.Equal(t, bid.ID, bids[0].ID)
+
+	_, err = db.AddFeedbackBid(ctx, bid.ID, "good offer", "alice")
+	require.NoError(t, err)
+	reviews, err := db.GetFeedback(ctx, tender.ID, "alice", "carol", models.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "good offer", reviews[0].Description)
+}
+
+func TestGetTenders_SnippetEscapesDescription(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	addEmployee(t, db, "alice")
+
+	organization := models.Organization{ID: uuid.GenerateCorrelationID(), Name: "Org"}
+	require.NoError(t, db.CreateOrganization(ctx, organization, "alice"))
+	tender := models.Tender{ID: uuid.GenerateCorrelationID(), Name: "Bridge", Description: `<img src=x onerror="alert(1)"> bridge`, ServiceType: "Construction", Status: "Published", OrganizationID: organization.ID, Version: 1, CreatedAt: time.Now()}
+	require.NoError(t, db.AddTender(ctx, tender, "alice"))
+
+	tenders, err := db.GetTenders(ctx, models.TenderFilter{Limit: 10, Query: "bridge"})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	// Разметка из описания экранируется, в сниппете остаётся только подсветка
+	assert.NotContains(t, tenders[0].Snippet, "<img")
+	assert.Contains(t, tenders[0].Snippet, "<b>bridge</b>")
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+var tracer = otel.Tracer("avito.go/internal/storage")
+
+// startQuery открывает span одного SQL-запроса; вложенные в вызов хранилища проверки прав и существования
+// становятся отдельными span'ами, и по ним видно, какая из них медленная
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+	return tracer.Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		))
+}
+
+func endQuery(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Rows - результат запроса, span которого закрывается вместе с ним: в span попадает и чтение строк,
+// а ошибка, прервавшая итерацию (rows.Err), записывается в span
+type Rows struct {
+	*sql.Rows
+	span   trace.Span
+	closed bool
+}
+
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		iterErr := r.Rows.Err()
+		if iterErr == nil {
+			iterErr = err
+		}
+		endQuery(r.span, iterErr)
+	}
+	return err
+}
+
+// queryRows открывает span запроса; при успехе он закрывается в Rows.Close, при ошибке - сразу
+func queryRows(ctx context.Context, query string, run func(ctx context.Context) (*sql.Rows, error)) (*Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := run(ctx)
+	if err != nil {
+		endQuery(span, err)
+		return nil, err
+	}
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+// QueryContext, ExecContext и QueryRowContext - обращения к пулу соединений с span'ом на каждый запрос
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return queryRows(ctx, query, func(ctx context.Context) (*sql.Rows, error) {
+		return db.DB.QueryContext(ctx, query, args...)
+	})
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	return result, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	endQuery(span, row.Err())
+	return row
+}
+
+// Tx - транзакция, запросы которой трассируются так же, как запросы DB
+type Tx struct {
+	*sql.Tx
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return queryRows(ctx, query, func(ctx context.Context) (*sql.Rows, error) {
+		return tx.Tx.QueryContext(ctx, query, args...)
+	})
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	return result, err
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	endQuery(span, row.Err())
+	return row
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+)
+
+// runner - общий интерфейс *sql.DB и *sql.Tx, чтобы вспомогательные запросы можно было выполнять внутри транзакции
+type runner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withTx выполняет fn в транзакции: коммит при успехе, откат при любой ошибке
+func (db *DB) withTx(ctx context.Context, fn func(tx *Tx) error) error {
+	sqlTx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return withRequestID(ctx, fmt.Errorf("error starting transaction: %w", err))
+	}
+	tx := &Tx{sqlTx}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return withRequestID(ctx, fmt.Errorf("error committing transaction: %w", err))
+	}
+	return nil
+}
+
+// lockTender блокирует строку тендера до конца транзакции и возвращает его текущую версию
+func lockTender(ctx context.Context, tx runner, tenderID string) (int, error) {
+	query := squirrel.Select("version").
+		From("tender").
+		Where(squirrel.Eq{"id": tenderID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoTender
+		}
+		return 0, queryError(ctx, err)
+	}
+	return version, nil
+}
+
+// archiveTender сохраняет текущее состояние тендера в tender_history
+func archiveTender(ctx context.Context, tx runner, tenderID string) error {
+	queryHistory := squirrel.Insert("tender_history").
+		Columns("id", "tender_id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at", "submission_deadline", "decision_deadline").
+		Select(
+			squirrel.Select("uuid_generate_v4()", "id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "updated_at", "submission_deadline", "decision_deadline").
+				From("tender").
+				Where(squirrel.Eq{"id": tenderID}),
+		).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := queryHistory.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return nil
+}
+
+// updateTender записывает изменённые поля и поднимает версию тендера на единицу относительно заблокированной
+func updateTender(ctx context.Context, tx runner, tenderID string, version int, fields map[string]interface{}) error {
+	query := squirrel.Update("tender").
+		SetMap(fields).
+		Set("version", version+1).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": tenderID, "version": version}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return checkVersionUpdated(ctx, result)
+}
+
+// lockBid блокирует строку предложения до конца транзакции и возвращает его текущую версию
+func lockBid(ctx context.Context, tx runner, bidID string) (int, error) {
+	query := squirrel.Select("version").
+		From("bid").
+		Where(squirrel.Eq{"id": bidID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoBid
+		}
+		return 0, queryError(ctx, err)
+	}
+	return version, nil
+}
+
+// archiveBid сохраняет текущее состояние предложения в bid_history
+func archiveBid(ctx context.Context, tx runner, bidID string) error {
+	queryHistory := squirrel.Insert("bid_history").
+		Columns("id", "bid_id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
+		Select(
+			squirrel.Select("uuid_generate_v4()", "id", "name", "description", "status", "tender_id", "author_type", "author_id", "version", "created_at", "updated_at").
+				From("bid").
+				Where(squirrel.Eq{"id": bidID}),
+		).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := queryHistory.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return nil
+}
+
+// updateBid записывает изменённые поля и поднимает версию предложения на единицу относительно заблокированной
+func updateBid(ctx context.Context, tx runner, bidID string, version int, fields map[string]interface{}) error {
+	query := squirrel.Update("bid").
+		SetMap(fields).
+		Set("version", version+1).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": bidID, "version": version}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	return checkVersionUpdated(ctx, result)
+}
+
+// checkVersionUpdated проверяет, что UPDATE с условием на версию изменил строку. Ноль строк значит,
+// что версию успели сменить в обход блокировки, и изменение не должно молча считаться успешным.
+func checkVersionUpdated(ctx context.Context, result sql.Result) error {
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	if updated == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// lockedStatus читает статус сущности внутри транзакции, после lockTender или lockBid
+func lockedStatus(ctx context.Context, tx runner, table, id string) (string, error) {
+	query := squirrel.Select("status").
+		From(table).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var status string
+	if err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&status); err != nil {
+		return "", queryError(ctx, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"avito.go/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// GetTenderVersions возвращает снимки тендера от новой версии к старой, включая текущую
+func (db *DB) GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return nil, ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return nil, ErrNoTender
+	}
+	check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+	if !check {
+		return nil, ErrRights
+	}
+
+	sqlQuery := `
+		SELECT id, name, description, service_type, status, organization_id, version, created_at, updated_at, submission_deadline, decision_deadline
+		FROM tender WHERE id = $1
+		UNION ALL
+		SELECT tender_id, name, description, service_type, status, organization_id, version, created_at, updated_at, submission_deadline, decision_deadline
+		FROM tender_history WHERE tender_id = $1
+		ORDER BY version DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := db.QueryContext(ctx, sqlQuery, tenderID, limit, offset)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	tenders := []models.Tender{}
+	for rows.Next() {
+		var tender models.Tender
+		if err = rows.Scan(
+			&tender.ID,
+			&tender.Name,
+			&tender.Description,
+			&tender.ServiceType,
+			&tender.Status,
+			&tender.OrganizationID,
+			&tender.Version,
+			&tender.CreatedAt,
+			&tender.UpdatedAt,
+			&tender.SubmissionDeadline,
+			&tender.DecisionDeadline); err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+	return tenders, rows.Err()
+}
+
+// DiffTenderVersions сравнивает две версии тендера по полям
+func (db *DB) DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.VersionDiff{}, ErrNoUser
+	}
+	TenderExist, _ := GetTender(ctx, db, tenderID)
+	if !TenderExist {
+		return models.VersionDiff{}, ErrNoTender
+	}
+	check, _ := isUserResponsibleForTender(ctx, db, username, tenderID)
+	if !check {
+		return models.VersionDiff{}, ErrRights
+	}
+
+	fromTender, err := tenderVersion(ctx, db, tenderID, from)
+	if err != nil {
+		return models.VersionDiff{}, err
+	}
+	toTender, err := tenderVersion(ctx, db, tenderID, to)
+	if err != nil {
+		return models.VersionDiff{}, err
+	}
+	return models.DiffTenders(fromTender, toTender), nil
+}
+
+// tenderVersion ищет снимок тендера сначала среди текущих данных, затем в истории
+func tenderVersion(ctx context.Context, r runner, tenderID string, version int) (models.Tender, error) {
+	sqlQuery := `
+		SELECT id, name, description, service_type, status, organization_id, version, created_at, updated_at, submission_deadline, decision_deadline
+		FROM tender WHERE id = $1 AND version = $2
+		UNION ALL
+		SELECT tender_id, name, description, service_type, status, organization_id, version, created_at, updated_at, submission_deadline, decision_deadline
+		FROM tender_history WHERE tender_id = $1 AND version = $2
+		LIMIT 1`
+
+	var tender models.Tender
+	err := r.QueryRowContext(ctx, sqlQuery, tenderID, version).Scan(
+		&tender.ID,
+		&tender.Name,
+		&tender.Description,
+		&tender.ServiceType,
+		&tender.Status,
+		&tender.OrganizationID,
+		&tender.Version,
+		&tender.CreatedAt,
+		&tender.UpdatedAt,
+		&tender.SubmissionDeadline,
+		&tender.DecisionDeadline,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Tender{}, ErrNoVersion
+		}
+		return models.Tender{}, queryError(ctx, err)
+	}
+	return tender, nil
+}
+
+// GetBidVersions возвращает снимки предложения от новой версии к старой, включая текущую
+func (db *DB) GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return nil, ErrNoUser
+	}
+	BidExist, _ := GetBid(ctx, db, bidID)
+	if !BidExist {
+		return nil, ErrNoBid
+	}
+	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidID)
+	if !check {
+		return nil, ErrRights
+	}
+
+	sqlQuery := `
+		SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at
+		FROM bid WHERE id = $1
+		UNION ALL
+		SELECT bid_id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at
+		FROM bid_history WHERE bid_id = $1
+		ORDER BY version DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := db.QueryContext(ctx, sqlQuery, bidID, limit, offset)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	bids := []models.Bid{}
+	for rows.Next() {
+		var bid models.Bid
+		if err = rows.Scan(
+			&bid.ID,
+			&bid.Name,
+			&bid.Description,
+			&bid.Status,
+			&bid.TenderID,
+			&bid.AuthorType,
+			&bid.AuthorID,
+			&bid.Version,
+			&bid.CreatedAt,
+			&bid.UpdatedAt); err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
+
+// DiffBidVersions сравнивает две версии предложения по полям
+func (db *DB) DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error) {
+	userExist, _ := GetUser(ctx, db, username)
+	if !userExist {
+		return models.VersionDiff{}, ErrNoUser
+	}
+	BidExist, _ := GetBid(ctx, db, bidID)
+	if !BidExist {
+		return models.VersionDiff{}, ErrNoBid
+	}
+	check, _ := isUserResponsibleToUpdateBid(ctx, db, username, bidID)
+	if !check {
+		return models.VersionDiff{}, ErrRights
+	}
+
+	fromBid, err := bidVersion(ctx, db, bidID, from)
+	if err != nil {
+		return models.VersionDiff{}, err
+	}
+	toBid, err := bidVersion(ctx, db, bidID, to)
+	if err != nil {
+		return models.VersionDiff{}, err
+	}
+	return models.DiffBids(fromBid, toBid), nil
+}
+
+// bidVersion ищет снимок предложения сначала среди текущих данных, затем в истории
+func bidVersion(ctx context.Context, r runner, bidID string, version int) (models.Bid, error) {
+	sqlQuery := `
+		SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at
+		FROM bid WHERE id = $1 AND version = $2
+		UNION ALL
+		SELECT bid_id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at
+		FROM bid_history WHERE bid_id = $1 AND version = $2
+		LIMIT 1`
+
+	var bid models.Bid
+	err := r.QueryRowContext(ctx, sqlQuery, bidID, version).Scan(
+		&bid.ID,
+		&bid.Name,
+		&bid.Description,
+		&bid.Status,
+		&bid.TenderID,
+		&bid.AuthorType,
+		&bid.AuthorID,
+		&bid.Version,
+		&bid.CreatedAt,
+		&bid.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Bid{}, ErrNoVersion
+		}
+		return models.Bid{}, queryError(ctx, err)
+	}
+	return bid, nil
+}
@@ -0,0 +1,312 @@
+package tracing
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+var tracer = otel.Tracer("avito.go/internal/tracing")
+
+// Storage открывает span на каждый вызов хранилища; span'ы отдельных SQL-запросов storage.DB вкладываются в него
+type Storage struct {
+	storage.Storage
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+func NewStorage(s storage.Storage) *Storage {
+	return &Storage{Storage: s}
+}
+
+func start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "storage."+method)
+}
+
+// end помечает span ошибкой только для неожиданных ошибок: отказ в правах или отсутствие сущности - обычный ответ API
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !storage.IsDomainError(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+func (s *Storage) AddTender(ctx context.Context, tender models.Tender, username string) error {
+	ctx, span := start(ctx, "AddTender")
+	err := s.Storage.AddTender(ctx, tender, username)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) GetMyTenders(ctx context.Context, page models.Page, username string) ([]models.Tender, error) {
+	ctx, span := start(ctx, "GetMyTenders")
+	tenders, err := s.Storage.GetMyTenders(ctx, page, username)
+	end(span, err)
+	return tenders, err
+}
+
+func (s *Storage) GetTenderStatus(ctx context.Context, tenderID, username string) (string, error) {
+	ctx, span := start(ctx, "GetTenderStatus")
+	status, err := s.Storage.GetTenderStatus(ctx, tenderID, username)
+	end(span, err)
+	return status, err
+}
+
+func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderID, status, username string) (models.Tender, error) {
+	ctx, span := start(ctx, "UpdateTenderStatus")
+	tender, err := s.Storage.UpdateTenderStatus(ctx, tenderID, status, username)
+	end(span, err)
+	return tender, err
+}
+
+func (s *Storage) RollbackTender(ctx context.Context, tenderID string, version int, username string) (models.Tender, error) {
+	ctx, span := start(ctx, "RollbackTender")
+	tender, err := s.Storage.RollbackTender(ctx, tenderID, version, username)
+	end(span, err)
+	return tender, err
+}
+
+func (s *Storage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]string, error) {
+	ctx, span := start(ctx, "CloseExpiredTenders")
+	ids, err := s.Storage.CloseExpiredTenders(ctx, now)
+	end(span, err)
+	return ids, err
+}
+
+func (s *Storage) GetTenders(ctx context.Context, filter models.TenderFilter) ([]models.Tender, error) {
+	ctx, span := start(ctx, "GetTenders")
+	tenders, err := s.Storage.GetTenders(ctx, filter)
+	end(span, err)
+	return tenders, err
+}
+
+func (s *Storage) EditTender(ctx context.Context, tenderId, username, tenderName, description, serviceType, status string, expectedVersion int) (models.Tender, error) {
+	ctx, span := start(ctx, "EditTender")
+	tender, err := s.Storage.EditTender(ctx, tenderId, username, tenderName, description, serviceType, status, expectedVersion)
+	end(span, err)
+	return tender, err
+}
+
+func (s *Storage) GetTenderVersions(ctx context.Context, tenderID, username string, limit, offset int) ([]models.Tender, error) {
+	ctx, span := start(ctx, "GetTenderVersions")
+	tenders, err := s.Storage.GetTenderVersions(ctx, tenderID, username, limit, offset)
+	end(span, err)
+	return tenders, err
+}
+
+func (s *Storage) DiffTenderVersions(ctx context.Context, tenderID, username string, from, to int) (models.VersionDiff, error) {
+	ctx, span := start(ctx, "DiffTenderVersions")
+	diff, err := s.Storage.DiffTenderVersions(ctx, tenderID, username, from, to)
+	end(span, err)
+	return diff, err
+}
+
+func (s *Storage) AddBid(ctx context.Context, bid models.Bid, userID string) error {
+	ctx, span := start(ctx, "AddBid")
+	err := s.Storage.AddBid(ctx, bid, userID)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) GetMyBids(ctx context.Context, page models.Page, username string) ([]models.Bid, error) {
+	ctx, span := start(ctx, "GetMyBids")
+	bids, err := s.Storage.GetMyBids(ctx, page, username)
+	end(span, err)
+	return bids, err
+}
+
+func (s *Storage) GetBidStatus(ctx context.Context, bidID, username string) (string, error) {
+	ctx, span := start(ctx, "GetBidStatus")
+	status, err := s.Storage.GetBidStatus(ctx, bidID, username)
+	end(span, err)
+	return status, err
+}
+
+func (s *Storage) UpdateBidStatus(ctx context.Context, bidID, status, username string) (models.Bid, error) {
+	ctx, span := start(ctx, "UpdateBidStatus")
+	bid, err := s.Storage.UpdateBidStatus(ctx, bidID, status, username)
+	end(span, err)
+	return bid, err
+}
+
+func (s *Storage) RollbackBid(ctx context.Context, bidID string, version int, username string) (models.Bid, error) {
+	ctx, span := start(ctx, "RollbackBid")
+	bid, err := s.Storage.RollbackBid(ctx, bidID, version, username)
+	end(span, err)
+	return bid, err
+}
+
+func (s *Storage) GetTenderBids(ctx context.Context, tenderId, username string, page models.Page) ([]models.Bid, error) {
+	ctx, span := start(ctx, "GetTenderBids")
+	bids, err := s.Storage.GetTenderBids(ctx, tenderId, username, page)
+	end(span, err)
+	return bids, err
+}
+
+func (s *Storage) SubmitDecisionBid(ctx context.Context, bidId string, decision string, username string) (models.Bid, error) {
+	ctx, span := start(ctx, "SubmitDecisionBid")
+	bid, err := s.Storage.SubmitDecisionBid(ctx, bidId, decision, username)
+	end(span, err)
+	return bid, err
+}
+
+func (s *Storage) EditBid(ctx context.Context, bidId, username, bidName, description, status string, expectedVersion int) (models.Bid, error) {
+	ctx, span := start(ctx, "EditBid")
+	bid, err := s.Storage.EditBid(ctx, bidId, username, bidName, description, status, expectedVersion)
+	end(span, err)
+	return bid, err
+}
+
+func (s *Storage) GetBidVersions(ctx context.Context, bidID, username string, limit, offset int) ([]models.Bid, error) {
+	ctx, span := start(ctx, "GetBidVersions")
+	bids, err := s.Storage.GetBidVersions(ctx, bidID, username, limit, offset)
+	end(span, err)
+	return bids, err
+}
+
+func (s *Storage) DiffBidVersions(ctx context.Context, bidID, username string, from, to int) (models.VersionDiff, error) {
+	ctx, span := start(ctx, "DiffBidVersions")
+	diff, err := s.Storage.DiffBidVersions(ctx, bidID, username, from, to)
+	end(span, err)
+	return diff, err
+}
+
+func (s *Storage) AddFeedbackBid(ctx context.Context, bidId string, bidFeedback string, username string) (models.Bid, error) {
+	ctx, span := start(ctx, "AddFeedbackBid")
+	bid, err := s.Storage.AddFeedbackBid(ctx, bidId, bidFeedback, username)
+	end(span, err)
+	return bid, err
+}
+
+func (s *Storage) GetFeedback(ctx context.Context, tenderId, authorUsername, requesterUsername string, page models.Page) ([]models.FeedBack, error) {
+	ctx, span := start(ctx, "GetFeedback")
+	feedback, err := s.Storage.GetFeedback(ctx, tenderId, authorUsername, requesterUsername, page)
+	end(span, err)
+	return feedback, err
+}
+
+func (s *Storage) GetUserCredentials(ctx context.Context, username string) (models.User, string, error) {
+	ctx, span := start(ctx, "GetUserCredentials")
+	user, hash, err := s.Storage.GetUserCredentials(ctx, username)
+	end(span, err)
+	return user, hash, err
+}
+
+func (s *Storage) CreateOrganization(ctx context.Context, organization models.Organization, username string) error {
+	ctx, span := start(ctx, "CreateOrganization")
+	err := s.Storage.CreateOrganization(ctx, organization, username)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	ctx, span := start(ctx, "GetOrganizations")
+	organizations, err := s.Storage.GetOrganizations(ctx, limit, offset)
+	end(span, err)
+	return organizations, err
+}
+
+func (s *Storage) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	ctx, span := start(ctx, "GetOrganization")
+	organization, err := s.Storage.GetOrganization(ctx, organizationID)
+	end(span, err)
+	return organization, err
+}
+
+func (s *Storage) EditOrganization(ctx context.Context, organizationID, username, name, description, organizationType string) (models.Organization, error) {
+	ctx, span := start(ctx, "EditOrganization")
+	organization, err := s.Storage.EditOrganization(ctx, organizationID, username, name, description, organizationType)
+	end(span, err)
+	return organization, err
+}
+
+func (s *Storage) DeleteOrganization(ctx context.Context, organizationID, username string) error {
+	ctx, span := start(ctx, "DeleteOrganization")
+	err := s.Storage.DeleteOrganization(ctx, organizationID, username)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	ctx, span := start(ctx, "GetOrganizationResponsibles")
+	users, err := s.Storage.GetOrganizationResponsibles(ctx, organizationID)
+	end(span, err)
+	return users, err
+}
+
+func (s *Storage) AddOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	ctx, span := start(ctx, "AddOrganizationResponsible")
+	err := s.Storage.AddOrganizationResponsible(ctx, organizationID, username, employeeUsername)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) RemoveOrganizationResponsible(ctx context.Context, organizationID, username, employeeUsername string) error {
+	ctx, span := start(ctx, "RemoveOrganizationResponsible")
+	err := s.Storage.RemoveOrganizationResponsible(ctx, organizationID, username, employeeUsername)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) GetEmployees(ctx context.Context, prefix string, limit, offset int) ([]models.User, error) {
+	ctx, span := start(ctx, "GetEmployees")
+	users, err := s.Storage.GetEmployees(ctx, prefix, limit, offset)
+	end(span, err)
+	return users, err
+}
+
+func (s *Storage) GetEmployee(ctx context.Context, username string) (models.User, error) {
+	ctx, span := start(ctx, "GetEmployee")
+	user, err := s.Storage.GetEmployee(ctx, username)
+	end(span, err)
+	return user, err
+}
+
+func (s *Storage) GetEmployeeOrganizations(ctx context.Context, username string) ([]models.Organization, error) {
+	ctx, span := start(ctx, "GetEmployeeOrganizations")
+	organizations, err := s.Storage.GetEmployeeOrganizations(ctx, username)
+	end(span, err)
+	return organizations, err
+}
+
+func (s *Storage) GetAuditEvents(ctx context.Context, filter models.AuditFilter, page models.Page, username string) ([]models.AuditEvent, error) {
+	ctx, span := start(ctx, "GetAuditEvents")
+	events, err := s.Storage.GetAuditEvents(ctx, filter, page, username)
+	end(span, err)
+	return events, err
+}
+
+func (s *Storage) CanAttach(ctx context.Context, entityType, entityID, username string) error {
+	ctx, span := start(ctx, "CanAttach")
+	err := s.Storage.CanAttach(ctx, entityType, entityID, username)
+	end(span, err)
+	return err
+}
+
+func (s *Storage) AddAttachment(ctx context.Context, attachment models.Attachment, username string) (models.Attachment, error) {
+	ctx, span := start(ctx, "AddAttachment")
+	attachment, err := s.Storage.AddAttachment(ctx, attachment, username)
+	end(span, err)
+	return attachment, err
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, entityType, entityID string, version int, username string) ([]models.Attachment, error) {
+	ctx, span := start(ctx, "GetAttachments")
+	attachments, err := s.Storage.GetAttachments(ctx, entityType, entityID, version, username)
+	end(span, err)
+	return attachments, err
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID, username string) (models.Attachment, error) {
+	ctx, span := start(ctx, "GetAttachment")
+	attachment, err := s.Storage.GetAttachment(ctx, attachmentID, username)
+	end(span, err)
+	return attachment, err
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Экспортёры span'ов, которые можно выбрать в TRACING_EXPORTER
+const (
+	ExporterNone   = "none"   // span'ы не записываются, но контекст трассировки из запросов передаётся дальше
+	ExporterStdout = "stdout" // span'ы печатаются в stdout, удобно для локальной отладки
+	ExporterOTLP   = "otlp"   // OTLP/HTTP; адрес и заголовки берутся из стандартных переменных OTEL_EXPORTER_OTLP_*
+)
+
+// Setup настраивает глобальные провайдер трассировки и W3C-пропагатор (traceparent, baggage).
+// Возвращает функцию, которая дописывает накопленные span'ы при остановке сервиса.
+func Setup(ctx context.Context, exporter, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case ExporterNone, "":
+		// Провайдер по умолчанию в otel - no-op, сервис работает без коллектора
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
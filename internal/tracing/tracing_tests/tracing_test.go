@@ -0,0 +1,62 @@
+package tracing_test
+
+import (
+	"avito.go/internal/models"
+	"avito.go/internal/storage"
+	"avito.go/internal/storage/memory"
+	"avito.go/internal/tracing"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStorage_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mem := memory.New()
+	require.NoError(t, mem.Load(memory.Seed{
+		Employees:     []memory.SeedEmployee{{User: models.User{ID: "u1", Username: "alice"}}, {User: models.User{ID: "u2", Username: "dave"}}},
+		Organizations: []models.Organization{{ID: "o1", Name: "Org"}},
+		Responsibles:  []memory.SeedResponsible{{OrganizationID: "o1", Username: "alice"}},
+		Tenders:       []models.Tender{{ID: "t1", Name: "Tender", Status: "Created", OrganizationID: "o1", Version: 1}},
+	}))
+	s := tracing.NewStorage(mem)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	status, err := s.GetTenderStatus(ctx, "t1", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Created", status)
+	_, err = s.GetTenderStatus(ctx, "t1", "dave")
+	assert.ErrorIs(t, err, storage.ErrRights)
+	err = s.RemoveOrganizationResponsible(ctx, "o1", "alice", "dave")
+	assert.ErrorIs(t, err, storage.ErrNotResponsible)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	for _, span := range spans[:2] {
+		assert.Equal(t, "storage.GetTenderStatus", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	// Ожидаемые ошибки API записываются событием, но не делают span ошибочным
+	for _, span := range spans[1:3] {
+		assert.Len(t, span.Events(), 1)
+		assert.NotEqual(t, codes.Error, span.Status().Code)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.ExporterNone, "test")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), "jaeger", "test")
+	assert.Error(t, err)
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Без блокировки строки предложения параллельные запросы могли записать повторный голос: оставляем самый ранний
+DELETE FROM decisions d
+    USING decisions o
+    WHERE d.bid_id = o.bid_id AND d.created_by = o.created_by AND (d.created_at, d.ctid) > (o.created_at, o.ctid);
+
+-- Один ответственный может проголосовать по предложению только один раз
+CREATE UNIQUE INDEX IF NOT EXISTS uq_decisions_bid_created_by ON decisions (bid_id, created_by);
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_decisions_bid_created_by;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Прежний код без транзакций мог записать одну версию в историю дважды: оставляем по одной строке на версию
+DELETE FROM tender_history h
+    USING tender_history d
+    WHERE h.tender_id = d.tender_id AND h.version = d.version AND h.ctid > d.ctid;
+DELETE FROM bid_history h
+    USING bid_history d
+    WHERE h.bid_id = d.bid_id AND h.version = d.version AND h.ctid > d.ctid;
+
+-- Каждая версия сущности попадает в историю ровно один раз
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tender_history_version ON tender_history (tender_id, version);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bid_history_version ON bid_history (bid_id, version);
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_bid_history_version;
+DROP INDEX IF EXISTS uq_tender_history_version;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Хеш пароля (bcrypt) для выдачи токенов сотрудникам
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS password_hash TEXT;
+
+-- +goose Down
+ALTER TABLE employee DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Раньше повторное назначение не проверялось: оставляем по одной строке на пару организация - сотрудник
+DELETE FROM organization_responsible d
+    USING organization_responsible o
+    WHERE d.organization_id = o.organization_id AND d.user_id = o.user_id AND d.ctid > o.ctid;
+
+-- Сотрудник назначается ответственным за организацию не более одного раза
+CREATE UNIQUE INDEX IF NOT EXISTS uq_organization_responsible ON organization_responsible (organization_id, user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_organization_responsible;
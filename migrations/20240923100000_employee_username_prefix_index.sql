@@ -0,0 +1,6 @@
+-- +goose Up
+-- Индекс для поиска сотрудников по началу username (LIKE 'prefix%')
+CREATE INDEX IF NOT EXISTS idx_employee_username_prefix ON employee (username text_pattern_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_employee_username_prefix;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Полнотекстовый поиск по тендерам: название весит больше описания.
+-- Конфигурация 'simple' без стемминга, потому что названия бывают и на русском, и на английском.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tender_search_vector ON tender USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tender_search_vector;
+ALTER TABLE tender DROP COLUMN IF EXISTS search_vector;
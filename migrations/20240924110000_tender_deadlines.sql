@@ -0,0 +1,17 @@
+-- +goose Up
+-- Необязательные сроки тендера: после submission_deadline предложения не принимаются,
+-- после последнего из сроков планировщик закрывает тендер. Время хранится в UTC.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMP;
+ALTER TABLE tender_history ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP;
+ALTER TABLE tender_history ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tender_published_deadline ON tender (COALESCE(decision_deadline, submission_deadline))
+    WHERE status = 'Published';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tender_published_deadline;
+ALTER TABLE tender_history DROP COLUMN IF EXISTS decision_deadline;
+ALTER TABLE tender_history DROP COLUMN IF EXISTS submission_deadline;
+ALTER TABLE tender DROP COLUMN IF EXISTS decision_deadline;
+ALTER TABLE tender DROP COLUMN IF EXISTS submission_deadline;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Журнал изменений тендеров и предложений: автор, действие, версии до и после и идентификатор запроса.
+-- organization_id - организация тендера, по ней журнал доступен ответственным. Внешнего ключа нет:
+-- журнал переживает удаление организации и её тендеров.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    version_before INT NOT NULL DEFAULT 0,
+    version_after INT NOT NULL DEFAULT 0,
+    correlation_id VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_organization ON audit_events (organization_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events (entity_type, entity_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Файлы тендеров и предложений. Содержимое лежит во внешнем хранилище по ключу id,
+-- version - версия сущности на момент загрузки, checksum - SHA-256 содержимого.
+CREATE TABLE IF NOT EXISTS attachment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    version INT NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    author VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_attachment_entity ON attachment (entity_type, entity_id, version);
+
+-- +goose Down
+DROP TABLE IF EXISTS attachment;
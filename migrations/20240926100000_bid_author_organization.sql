@@ -0,0 +1,11 @@
+-- +goose Up
+-- Автором предложения может быть организация: author_id хранит id сотрудника или организации в зависимости от author_type
+ALTER TABLE bid DROP CONSTRAINT IF EXISTS bid_author_id_fkey;
+ALTER TABLE bid_history DROP CONSTRAINT IF EXISTS bid_history_author_id_fkey;
+ALTER TABLE bid_reviews DROP CONSTRAINT IF EXISTS bid_reviews_bid_author_id_fkey;
+
+-- +goose Down
+-- NOT VALID: уже поданные предложения организаций не проверяются, ограничение действует для новых строк
+ALTER TABLE bid_reviews ADD CONSTRAINT bid_reviews_bid_author_id_fkey FOREIGN KEY (bid_author_id) REFERENCES employee(id) NOT VALID;
+ALTER TABLE bid_history ADD CONSTRAINT bid_history_author_id_fkey FOREIGN KEY (author_id) REFERENCES employee(id) NOT VALID;
+ALTER TABLE bid ADD CONSTRAINT bid_author_id_fkey FOREIGN KEY (author_id) REFERENCES employee(id) NOT VALID;
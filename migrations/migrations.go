@@ -0,0 +1,7 @@
+// Package migrations встраивает goose-миграции в бинарник, чтобы сервис применял их при старте без внешних файлов.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"avito.go/internal/storage"
+	"avito.go/migrations"
+	"io/fs"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations_Collect(t *testing.T) {
+	goose.SetBaseFS(migrations.FS)
+	defer goose.SetBaseFS(nil)
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+
+	collected, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+	require.NoError(t, err)
+	assert.Len(t, collected, len(files))
+}
+
+func TestLatestMigration(t *testing.T) {
+	defer goose.SetBaseFS(nil)
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+	last, err := goose.NumericComponent(files[len(files)-1])
+	require.NoError(t, err)
+
+	latest, err := storage.LatestMigration()
+	require.NoError(t, err)
+	assert.Equal(t, last, latest)
+}
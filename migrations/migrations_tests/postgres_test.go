@@ -0,0 +1,82 @@
+package migrations_test
+
+import (
+	"avito.go/internal/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB подключается к базе из TEST_POSTGRES_CONN (URL postgres://...) в отдельной пустой схеме,
+// которая удаляется после теста. Без переменной окружения тест пропускается.
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_POSTGRES_CONN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	db, err := sql.Open("pgx", u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestHistoryUniqueVersions_RemovesDuplicates(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, storage.Migrate(ctx, db, "up-to", "20240920120000"))
+
+	// Дубликаты версий, которые мог оставить код до перехода на транзакции
+	seed := []string{
+		`INSERT INTO employee (id, username) VALUES ('00000000-0000-0000-0000-000000000001', 'user1')`,
+		`INSERT INTO organization (id, name) VALUES ('00000000-0000-0000-0000-000000000002', 'Org')`,
+		`INSERT INTO tender (id, name, description, service_type, status, organization_id)
+			VALUES ('00000000-0000-0000-0000-000000000003', 'Tender', 'Desc', 'Delivery', 'Created', '00000000-0000-0000-0000-000000000002')`,
+		`INSERT INTO bid (id, name, description, status, tender_id, author_type, author_id)
+			VALUES ('00000000-0000-0000-0000-000000000004', 'Bid', 'Desc', 'Created', '00000000-0000-0000-0000-000000000003', 'User', '00000000-0000-0000-0000-000000000001')`,
+	}
+	for i := 0; i < 3; i++ {
+		seed = append(seed,
+			`INSERT INTO tender_history (tender_id, name, description, service_type, status, organization_id, version)
+				VALUES ('00000000-0000-0000-0000-000000000003', 'Tender', 'Desc', 'Delivery', 'Created', '00000000-0000-0000-0000-000000000002', 1)`,
+			`INSERT INTO bid_history (bid_id, name, description, status, tender_id, author_type, author_id, version)
+				VALUES ('00000000-0000-0000-0000-000000000004', 'Bid', 'Desc', 'Created', '00000000-0000-0000-0000-000000000003', 'User', '00000000-0000-0000-0000-000000000001', 1)`)
+	}
+	for _, statement := range seed {
+		_, err := db.ExecContext(ctx, statement)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, storage.Migrate(ctx, db, "up-to", "20240920130000"))
+
+	for _, table := range []string{"tender_history", "bid_history"} {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count))
+		assert.Equal(t, 1, count, table)
+	}
+	require.NoError(t, storage.Migrate(ctx, db, "up"))
+}
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalid = errors.New("invalid cursor")
+
+// payload - содержимое курсора до кодирования
+type payload struct {
+	Order string   `json:"o"`
+	Keys  []string `json:"k"`
+}
+
+// Encode упаковывает ключ последней записи страницы в непрозрачную строку.
+// order - имя упорядочивания выборки: курсор принимается только той же выборкой.
+func Encode(order string, keys ...string) string {
+	data, _ := json.Marshal(payload{Order: order, Keys: keys})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode извлекает ключ из курсора. Пустой курсор означает первую страницу и возвращает nil.
+func Decode(value, order string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	var p payload
+	if err = json.Unmarshal(data, &p); err != nil || p.Order != order || len(p.Keys) == 0 {
+		return nil, ErrInvalid
+	}
+	return p.Keys, nil
+}
@@ -0,0 +1,29 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	keys, err := Decode(Encode("name", "Road repair", "t1"), "name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Road repair", "t1"}, keys)
+
+	keys, err = Decode("", "name")
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"not base64!",
+		"bm90IGpzb24",
+		Encode("createdAt", "2024-09-01T00:00:00Z", "t1"),
+		Encode("name"),
+	} {
+		_, err := Decode(value, "name")
+		assert.ErrorIs(t, err, ErrInvalid, value)
+	}
+}
@@ -0,0 +1,40 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid entity tag")
+
+// ErrWeak - слабый ETag в If-Match: RFC 9110 требует сильного сравнения, поэтому он никогда не совпадает с версией
+var ErrWeak = errors.New("weak entity tag")
+
+// Format строит сильный ETag из номера версии сущности
+func Format(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Parse извлекает номер версии из заголовка If-Match.
+// Пустой заголовок и "*" означают отсутствие условия и возвращают 0.
+// Корректный слабый тег возвращает ErrWeak.
+func Parse(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	weak := strings.HasPrefix(header, "W/")
+	header = strings.TrimPrefix(header, "W/")
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return 0, ErrInvalid
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 1 {
+		return 0, ErrInvalid
+	}
+	if weak {
+		return 0, ErrWeak
+	}
+	return version, nil
+}
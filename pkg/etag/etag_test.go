@@ -0,0 +1,44 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int
+		wantErr bool
+	}{
+		{header: "", version: 0},
+		{header: "*", version: 0},
+		{header: `"3"`, version: 3},
+		{header: `3`, wantErr: true},
+		{header: `"abc"`, wantErr: true},
+		{header: `"0"`, wantErr: true},
+		{header: `W/"abc"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		version, err := Parse(tt.header)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, ErrInvalid, tt.header)
+			continue
+		}
+		assert.NoError(t, err, tt.header)
+		assert.Equal(t, tt.version, version, tt.header)
+	}
+}
+
+func TestParse_Weak(t *testing.T) {
+	_, err := Parse(`W/"12"`)
+	assert.ErrorIs(t, err, ErrWeak)
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	version, err := Parse(Format(7))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, version)
+}
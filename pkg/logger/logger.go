@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"avito.go/pkg/requestid"
+	"context"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -20,3 +23,15 @@ func InitLogger() error {
 
 	return nil
 }
+
+// FromContext возвращает Log с полями request_id и trace_id, если они есть в контексте
+func FromContext(ctx context.Context) *zap.Logger {
+	log := Log
+	if id := requestid.FromContext(ctx); id != "" {
+		log = log.With(zap.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		log = log.With(zap.String("trace_id", span.TraceID().String()))
+	}
+	return log
+}
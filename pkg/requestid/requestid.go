@@ -0,0 +1,39 @@
+package requestid
+
+import "context"
+
+// Header - заголовок, в котором клиент передаёт идентификатор запроса и в котором сервер его возвращает
+const Header = "X-Request-ID"
+
+// maxLength ограничивает длину идентификатора, пришедшего от клиента
+const maxLength = 128
+
+type ctxKey struct{}
+
+// NewContext сохраняет идентификатор запроса в контексте
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса или пустую строку, если его нет
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Valid проверяет идентификатор от клиента: непустой, не длиннее maxLength,
+// только буквы, цифры и символы "-", "_", ".", ":" - чтобы его можно было без экранирования писать в логи и заголовки
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
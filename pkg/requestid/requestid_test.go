@@ -0,0 +1,26 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), "req-1")
+	assert.Equal(t, "req-1", FromContext(ctx))
+}
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid("5f0c6f7e-2b1d-4c55-9d1a-0b7e2a9c4e11"))
+	assert.True(t, Valid("gateway:42.7_a"))
+
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("with space"))
+	assert.False(t, Valid("line\nbreak"))
+	assert.False(t, Valid(strings.Repeat("a", 129)))
+}